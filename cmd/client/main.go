@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/win5do/go-lib/errx"
 	log "github.com/win5do/go-lib/logx"
@@ -18,7 +19,7 @@ import (
 )
 
 func main() {
-	var service string
+	var service, token string
 
 	rootCmd := &cobra.Command{
 		Use:   "client",
@@ -27,18 +28,19 @@ func main() {
 			log.SetLogger(log.NewLogger(zapcore.DebugLevel))
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run(service)
+			return run(service, token)
 		},
 	}
 
 	rootCmd.Flags().StringVar(&service, "service", "", "headless service address")
+	rootCmd.Flags().StringVar(&token, "token", "", "jwt bearer token")
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("err: %+v", err)
 	}
 }
 
-func run(addr string) error {
+func run(addr, token string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	conn, err := grpc.DialContext(ctx, "dns:///"+addr,
 		grpc.WithInsecure(),
@@ -50,10 +52,15 @@ func run(addr string) error {
 		return errx.WithStackOnce(err)
 	}
 
+	ctx = context.Background()
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
 	ticker := time.NewTicker(1000 * time.Millisecond)
 	for t := range ticker.C {
 		client := petpb.NewPetServiceClient(conn)
-		resp, err := client.Ping(context.Background(), &petpb.Id{
+		resp, err := client.Ping(ctx, &petpb.Id{
 			Id: echo(t),
 		})
 		if err != nil {
@@ -1,22 +1,34 @@
 package main
 
 import (
+	"context"
 	goflag "flag"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/win5do/golang-microservice-demo/pkg/config"
 	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	"github.com/win5do/golang-microservice-demo/pkg/election"
+	"github.com/win5do/golang-microservice-demo/pkg/job"
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbinit"
+	dbjob "github.com/win5do/golang-microservice-demo/pkg/repository/db/job"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+	petdb "github.com/win5do/golang-microservice-demo/pkg/repository/db/pet"
+	memjob "github.com/win5do/golang-microservice-demo/pkg/repository/memory/job"
+	mempet "github.com/win5do/golang-microservice-demo/pkg/repository/memory/pet"
+	adminsvc "github.com/win5do/golang-microservice-demo/pkg/service/admin"
+	petsvc "github.com/win5do/golang-microservice-demo/pkg/service/pet"
 
 	log "github.com/win5do/go-lib/logx"
 
 	grpcserver "github.com/win5do/golang-microservice-demo/pkg/server/grpc"
 	httpserver "github.com/win5do/golang-microservice-demo/pkg/server/http"
+	"github.com/win5do/golang-microservice-demo/pkg/server/supervisor"
 )
 
 func main() {
@@ -31,28 +43,62 @@ func main() {
 				return err
 			}
 
+			if cfg.Storage == config.StorageMemory {
+				return nil
+			}
+
 			// 连接数据库
-			dbcore.Connect(&cfg.DBConfig)
-			err = dbinit.InitData()
+			db, err := dbcore.Connect(cfg.Ctx, &cfg.DBConfig)
 			if err != nil {
 				return err
 			}
+			cfg.DB = db
+			// 兼容使用包级函数的代码
+			dbcore.SetDefault(db)
 			return nil
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			Run(cfg)
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Storage == config.StorageMemory {
+				return nil
+			}
+
+			if cfg.AutoMigrate {
+				_, err := migration.Up(cfg.Ctx, cfg.DB, 0)
+				if err != nil {
+					return err
+				}
+			}
+
+			return dbinit.InitData(cfg.Ctx, cfg.DB, cfg.SeedDir)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 运行中的错误不打印用法
+			cmd.SilenceUsage = true
+			return Run(cfg)
 		},
 	}
 
-	config.SetFlags(rootCmd.Flags(), cfg)
-	rootCmd.Flags().AddGoFlagSet(goflag.CommandLine)
+	config.SetFlags(rootCmd.PersistentFlags(), cfg)
+	rootCmd.PersistentFlags().AddGoFlagSet(goflag.CommandLine)
+
+	rootCmd.AddCommand(newMigrateCmd(cfg), newSeedCmd(cfg))
+
+	err := rootCmd.Execute()
+
+	// PostRun 在出错时不执行，在这里关闭连接池，此时 Run 已等待所有 goroutine 结束
+	if cfg.DB != nil {
+		if err := cfg.DB.Close(); err != nil {
+			log.Errorf("close db err: %+v", err)
+		}
+	}
 
-	if err := rootCmd.Execute(); err != nil {
+	if err != nil {
 		log.Fatalf("err: %+v", err)
 	}
 }
 
-func Run(cfg *config.Config) {
+// 服务异常退出时返回错误，进程以非 0 退出
+func Run(cfg *config.Config) error {
 	ctx := cfg.Ctx
 	defer func() {
 		cfg.Cancel()
@@ -60,11 +106,60 @@ func Run(cfg *config.Config) {
 		util.GetWaitGroupInCtx(ctx).Wait() // wait for goroutine cancel
 	}()
 
-	// http
-	go httpserver.Run(ctx, cfg)
+	scheduler := newScheduler(cfg)
+
+	// 定时任务，开启选举时只在 leader 上调度，手动触发可以在任意副本执行
+	runJobs := func(ctx context.Context) {
+		scheduler.Run(ctx)
+	}
+
+	var elector *election.Elector
+	if cfg.Storage == config.StorageDb && cfg.LeaderElection {
+		elector = election.NewDbElector(cfg.DB, dbcore.GetHostname(), dbcore.DefaultLeaseAge, election.Callbacks{
+			OnStartedLeading: runJobs,
+		})
+	}
+
+	svcs := newServices(cfg, elector, scheduler)
+
+	// 清理软删除记录
+	if j := job.PurgeJob(cfg, svcs.Pet); j != nil {
+		if err := scheduler.Register(j); err != nil {
+			return err
+		}
+	} else {
+		log.Info("purge disabled")
+	}
+
+	// 后添加的先退出，http 最后退出，停止过程中仍可访问 /readyz 和 /metrics
+	sup := supervisor.New(cfg.ShutdownTimeout)
+	if cfg.MetricsPort != "" {
+		sup.Add("metrics", httpserver.NewMetrics(cfg))
+	}
+	sup.Add("http", httpserver.New(cfg))
+	if err := grpcserver.Setup(ctx, sup, cfg, svcs); err != nil {
+		return err
+	}
+
+	if cfg.Storage == config.StorageDb {
+		cfg.Health.Register("db", cfg.DB.Ping)
+		cfg.Health.Register("migration", migration.Check(cfg.DB))
+	}
+	go cfg.Health.Run(ctx, cfg.HealthCheckInterval)
+
+	if elector != nil {
+		go elector.Run(ctx)
+	} else {
+		go runJobs(ctx)
+	}
 
-	// grpc
-	go grpcserver.Run(ctx, cfg)
+	// 与 cfg.Ctx 分开，等待 ShutdownDelay 后再停止服务
+	supCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sup.Run(supCtx)
+	}()
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -72,6 +167,55 @@ func Run(cfg *config.Config) {
 	// kill -2 is syscall.SIGINT
 	// kill -9 is syscall. SIGKILL but can"t be catch, so don't need add it
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+
+	select {
+	case <-quit:
+	case err := <-errCh:
+		// 有服务异常退出，其他服务已停止
+		cfg.Health.Shutdown()
+		return err
+	}
 	log.Info("shutdown server ...")
+
+	// 先设为不可用，等负载均衡摘除后再停止 grpc 和 http
+	cfg.Health.Shutdown()
+	if cfg.ShutdownDelay > 0 {
+		log.Infof("wait %s before stopping servers", cfg.ShutdownDelay)
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	stop()
+	return <-errCh
+}
+
+func newScheduler(cfg *config.Config) *job.Scheduler {
+	if cfg.Storage == config.StorageMemory {
+		// 单副本，不需要加锁
+		return job.NewScheduler(cfg.Ctx, dbcore.GetHostname(), memjob.NewJobRunDb(), nil)
+	}
+
+	identity := dbcore.GetHostname()
+	return job.NewScheduler(cfg.Ctx, identity, dbjob.NewJobRunDb(cfg.DB), job.NewDbLocker(cfg.DB, identity))
+}
+
+func newServices(cfg *config.Config, elector *election.Elector, scheduler *job.Scheduler) *grpcserver.Services {
+	if cfg.Storage == config.StorageMemory {
+		store := mempet.NewStore()
+		return &grpcserver.Services{
+			Pet: petsvc.NewPetService(store, mempet.NewPetDomain(store)),
+			// 单副本没有选举，锁管理为空实现，任务管理仍然可用
+			Admin: adminsvc.NewAdminService(&adminsvc.NoopLockAdmin{}, nil, scheduler, cfg.AdminForceUnlock),
+		}
+	}
+
+	// 避免 nil 指针转为非 nil 接口
+	var e adminsvc.IElector
+	if elector != nil {
+		e = elector
+	}
+
+	return &grpcserver.Services{
+		Pet:   petsvc.NewPetService(dbcore.NewTxImpl(cfg.DB), petdb.NewPetDomain(cfg.DB)),
+		Admin: adminsvc.NewAdminService(dbcore.NewLockAdmin(cfg.DB), e, scheduler, cfg.AdminForceUnlock),
+	}
 }
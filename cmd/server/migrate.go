@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+)
+
+func newMigrateCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "database schema migration",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Storage != config.StorageDb {
+				return fmt.Errorf("migrate requires --storage=%s", config.StorageDb)
+			}
+			// 子命令会覆盖父命令的 PersistentPreRunE，需要手动调用
+			return cmd.Root().PersistentPreRunE(cmd, args)
+		},
+	}
+
+	var target int64
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n, err := migration.Up(cfg.Ctx, cfg.DB, target)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d migrations applied\n", n)
+			return nil
+		},
+	}
+	up.Flags().Int64Var(&target, "to", 0, "target version, 0 for latest")
+
+	down := &cobra.Command{
+		Use:   "down [steps]",
+		Short: "roll back the last applied migrations, 1 step by default",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			steps := 1
+			if len(args) > 0 {
+				var err error
+				steps, err = strconv.Atoi(args[0])
+				if err != nil || steps <= 0 {
+					return fmt.Errorf("invalid steps: %s", args[0])
+				}
+			}
+
+			n, err := migration.Down(cfg.Ctx, cfg.DB, steps)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d migrations rolled back\n", n)
+			return nil
+		},
+	}
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "show migration status",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statuses, err := migration.Statuses(cfg.Ctx, cfg.DB)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+			for _, v := range statuses {
+				appliedAt := "pending"
+				if v.Applied {
+					appliedAt = v.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\n", v.Version, v.Name, appliedAt)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.AddCommand(up, down, status)
+	return cmd
+}
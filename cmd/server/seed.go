@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbinit"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+)
+
+func newSeedCmd(cfg *config.Config) *cobra.Command {
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "apply seed data from --seed-dir, for dev and test environments",
+		Args:  cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Storage != config.StorageDb {
+				return fmt.Errorf("seed requires --storage=%s", config.StorageDb)
+			}
+			if cfg.SeedDir == "" {
+				return fmt.Errorf("--seed-dir is required")
+			}
+			return cmd.Root().PersistentPreRunE(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 种子数据依赖最新的表结构
+			if cfg.AutoMigrate {
+				if _, err := migration.Up(cfg.Ctx, cfg.DB, 0); err != nil {
+					return err
+				}
+			} else if err := migration.Check(cfg.DB)(cfg.Ctx); err != nil {
+				return fmt.Errorf("%w, run migrate up first or set --auto-migrate", err)
+			}
+
+			n, err := dbinit.ApplySeedDir(cfg.Ctx, cfg.DB, cfg.SeedDir, force)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d seeds applied\n", n)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "reapply seeds that have been applied")
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+// Package apidocs 打包进二进制的 swagger 文档，修改 proto 后执行 go generate 更新
+package apidocs
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+)
+
+//go:generate go run gen.go
+
+// 文档名称，与 proto 文件名相同，如 pet、admin
+func Names() []string {
+	r := make([]string, 0, len(specs))
+	for k := range specs {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+func Spec(name string) ([]byte, bool) {
+	v, ok := specs[name]
+	return []byte(v), ok
+}
+
+// redoc 从 cdn 加载，页面和文档都在二进制中
+var redocTmpl = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <title>{{.Name}} API</title>
+  <meta charset="utf-8"/>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <style>
+    body { margin: 0; padding: 0; }
+    nav { padding: 8px 16px; font-family: sans-serif; border-bottom: 1px solid #eee; }
+    nav a { margin-right: 16px; }
+  </style>
+</head>
+<body>
+  <nav>{{range .Names}}<a href="{{.}}">{{.}}</a>{{end}}</nav>
+  <redoc spec-url="{{.SpecURL}}"></redoc>
+  <script src="https://cdn.jsdelivr.net/npm/redoc@2.0.0-rc.45/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`))
+
+// 渲染 redoc 页面，specURL 为文档的访问地址
+func RedocPage(name, specURL string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := redocTmpl.Execute(buf, map[string]interface{}{
+		"Name":    name,
+		"Names":   Names(),
+		"SpecURL": specURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
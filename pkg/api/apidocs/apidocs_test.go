@@ -0,0 +1,33 @@
+package apidocs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// 修改 proto 后需要执行 go generate
+func TestSpecsUpToDate(t *testing.T) {
+	files, err := filepath.Glob("../*pb/*.swagger.json")
+	require.NoError(t, err)
+	require.Len(t, Names(), len(files))
+
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+
+		spec, ok := Spec(strings.TrimSuffix(filepath.Base(f), ".swagger.json"))
+		require.True(t, ok, f)
+		require.Equal(t, string(data), string(spec), "%s is outdated, run go generate", f)
+	}
+}
+
+func TestRedocPage(t *testing.T) {
+	page, err := RedocPage("pet", "/swagger/pet.swagger.json")
+	require.NoError(t, err)
+	require.Contains(t, string(page), `spec-url="/swagger/pet.swagger.json"`)
+	require.Contains(t, string(page), `href="admin"`)
+}
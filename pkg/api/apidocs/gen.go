@@ -0,0 +1,48 @@
+// +build ignore
+
+// 把 swagger.json 生成为 go 代码，go 1.15 没有 embed，通过 go generate 打包进二进制
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	files, err := filepath.Glob("../*pb/*.swagger.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(files)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by gen.go; DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package apidocs")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var specs = map[string]string{")
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		name := strings.TrimSuffix(filepath.Base(f), ".swagger.json")
+		fmt.Fprintf(buf, "%q: %q,\n", name, data)
+	}
+	fmt.Fprintln(buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("specs_gen.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,8 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package apidocs
+
+var specs = map[string]string{
+	"admin": "{\n  \"swagger\": \"2.0\",\n  \"info\": {\n    \"title\": \"admin.proto\",\n    \"version\": \"version not set\"\n  },\n  \"consumes\": [\n    \"application/json\"\n  ],\n  \"produces\": [\n    \"application/json\"\n  ],\n  \"paths\": {\n    \"/v1/admin/jobs\": {\n      \"get\": {\n        \"summary\": \"列出定时任务及最近一次执行\",\n        \"operationId\": \"AdminService_ListJobs\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1JobList\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"tags\": [\n          \"AdminService\"\n        ]\n      }\n    },\n    \"/v1/admin/jobs/{name}:trigger\": {\n      \"post\": {\n        \"summary\": \"手动触发定时任务，在后台执行，返回本次执行记录\",\n        \"operationId\": \"AdminService_TriggerJob\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1JobRun\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"name\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1TriggerJobRequest\"\n            }\n          }\n        ],\n        \"tags\": [\n          \"AdminService\"\n        ]\n      }\n    },\n    \"/v1/admin/leader\": {\n      \"get\": {\n        \"summary\": \"当前 leader\",\n        \"operationId\": \"AdminService_GetLeader\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Leader\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"tags\": [\n          \"AdminService\"\n        ]\n      }\n    },\n    \"/v1/admin/locks\": {\n      \"get\": {\n        \"summary\": \"列出当前持有的分布式锁\",\n        \"operationId\": \"AdminService_ListLocks\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1LockList\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"action\",\n            \"description\": \"为空返回全部.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"AdminService\"\n        ]\n      }\n    },\n    \"/v1/admin/locks/{action}:release\": {\n      \"post\": {\n        \"summary\": \"强制释放锁，持有者在下次续期时发现锁已丢失，需要开启 --admin-force-unlock\",\n        \"operationId\": \"AdminService_ReleaseLock\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1ReleaseLockResponse\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"action\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1ReleaseLockRequest\"\n            }\n          }\n        ],\n        \"tags\": [\n          \"AdminService\"\n        ]\n      }\n    }\n  },\n  \"definitions\": {\n    \"protobufAny\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"typeUrl\": {\n          \"type\": \"string\"\n        },\n        \"value\": {\n          \"type\": \"string\",\n          \"format\": \"byte\"\n        }\n      }\n    },\n    \"rpcStatus\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"code\": {\n          \"type\": \"integer\",\n          \"format\": \"int32\"\n        },\n        \"message\": {\n          \"type\": \"string\"\n        },\n        \"details\": {\n          \"type\": \"array\",\n          \"items\": {\n            \"$ref\": \"#/definitions/protobufAny\"\n          }\n        }\n      }\n    },\n    \"v1Job\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"name\": {\n          \"type\": \"string\"\n        },\n        \"spec\": {\n          \"type\": \"string\"\n        },\n        \"nextRunAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\",\n          \"title\": \"处理本次请求的副本的下次调度时间，未在调度时为空\"\n        },\n        \"running\": {\n          \"type\": \"boolean\",\n          \"title\": \"处理本次请求的副本正在执行\"\n        },\n        \"lastRun\": {\n          \"$ref\": \"#/definitions/v1JobRun\"\n        }\n      }\n    },\n    \"v1JobList\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"items\": {\n          \"type\": \"array\",\n          \"items\": {\n            \"$ref\": \"#/definitions/v1Job\"\n          }\n        }\n      }\n    },\n    \"v1JobRun\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        },\n        \"job\": {\n          \"type\": \"string\"\n        },\n        \"status\": {\n          \"type\": \"string\",\n          \"title\": \"running, succeeded 或 failed\"\n        },\n        \"error\": {\n          \"type\": \"string\"\n        },\n        \"holder\": {\n          \"type\": \"string\",\n          \"title\": \"执行的副本\"\n        },\n        \"manual\": {\n          \"type\": \"boolean\"\n        },\n        \"startedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"endedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        }\n      }\n    },\n    \"v1Leader\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"name\": {\n          \"type\": \"string\",\n          \"title\": \"选举使用的锁\"\n        },\n        \"holder\": {\n          \"type\": \"string\",\n          \"title\": \"当前 leader，为空表示正在选举\"\n        },\n        \"expiredAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"identity\": {\n          \"type\": \"string\",\n          \"title\": \"处理本次请求的副本\"\n        },\n        \"isLeader\": {\n          \"type\": \"boolean\"\n        }\n      }\n    },\n    \"v1Lock\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"action\": {\n          \"type\": \"string\"\n        },\n        \"holder\": {\n          \"type\": \"string\"\n        },\n        \"mode\": {\n          \"type\": \"string\",\n          \"title\": \"exclusive 或 shared\"\n        },\n        \"holds\": {\n          \"type\": \"integer\",\n          \"format\": \"int32\",\n          \"title\": \"重入次数\"\n        },\n        \"token\": {\n          \"type\": \"string\",\n          \"format\": \"int64\"\n        },\n        \"createdAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"expiredAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        }\n      }\n    },\n    \"v1LockList\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"items\": {\n          \"type\": \"array\",\n          \"items\": {\n            \"$ref\": \"#/definitions/v1Lock\"\n          }\n        }\n      }\n    },\n    \"v1ReleaseLockRequest\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"action\": {\n          \"type\": \"string\"\n        },\n        \"holder\": {\n          \"type\": \"string\",\n          \"title\": \"为空释放全部持有者\"\n        }\n      }\n    },\n    \"v1ReleaseLockResponse\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"released\": {\n          \"type\": \"string\",\n          \"format\": \"int64\",\n          \"title\": \"释放的持有者个数\"\n        }\n      }\n    },\n    \"v1TriggerJobRequest\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"name\": {\n          \"type\": \"string\"\n        }\n      }\n    }\n  }\n}\n",
+	"pet":   "{\n  \"swagger\": \"2.0\",\n  \"info\": {\n    \"title\": \"pet.proto\",\n    \"version\": \"version not set\"\n  },\n  \"consumes\": [\n    \"application/json\"\n  ],\n  \"produces\": [\n    \"application/json\"\n  ],\n  \"paths\": {\n    \"/ping\": {\n      \"get\": {\n        \"operationId\": \"PetService_Ping\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Id\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/owners\": {\n      \"get\": {\n        \"operationId\": \"PetService_ListOwner\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1OwnerList\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"pageSize\",\n            \"description\": \"每页条数，0 使用默认值.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"integer\",\n            \"format\": \"int32\"\n          },\n          {\n            \"name\": \"pageToken\",\n            \"description\": \"上一页返回的 next_page_token，为空表示第一页.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"filter\",\n            \"description\": \"过滤表达式，如：sex = \\\"female\\\" AND age \\u003e= 18.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"orderBy\",\n            \"description\": \"排序，如：created_at desc, age.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"showDeleted\",\n            \"description\": \"包含已删除的记录.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"boolean\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"post\": {\n        \"operationId\": \"PetService_CreateOwner\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/owners-pets\": {\n      \"delete\": {\n        \"operationId\": \"PetService_AbandonPet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"properties\": {}\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"createdAt\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\",\n            \"format\": \"date-time\"\n          },\n          {\n            \"name\": \"updatedAt\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\",\n            \"format\": \"date-time\"\n          },\n          {\n            \"name\": \"ownerId\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"petId\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"post\": {\n        \"operationId\": \"PetService_OwnPet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1OwnerPet\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/owners/{id}\": {\n      \"get\": {\n        \"operationId\": \"PetService_GetOwner\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"delete\": {\n        \"operationId\": \"PetService_DeleteOwner\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"properties\": {}\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"etag\",\n            \"description\": \"为空时使用 If-Match 请求头.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/owners/{id}:undelete\": {\n      \"post\": {\n        \"operationId\": \"PetService_UndeleteOwner\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1UndeleteOwnerRequest\"\n            }\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/owners/{owner.id}\": {\n      \"put\": {\n        \"operationId\": \"PetService_UpdateOwner\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"owner.id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          {\n            \"name\": \"updateMask\",\n            \"description\": \"需要更新的字段，为空时只更新非零值字段，\\\"*\\\" 更新全部字段.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"array\",\n            \"items\": {\n              \"type\": \"string\"\n            },\n            \"collectionFormat\": \"multi\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"patch\": {\n        \"operationId\": \"PetService_UpdateOwner2\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"owner.id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Owner\"\n            }\n          },\n          {\n            \"name\": \"updateMask\",\n            \"description\": \"需要更新的字段，为空时只更新非零值字段，\\\"*\\\" 更新全部字段.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"array\",\n            \"items\": {\n              \"type\": \"string\"\n            },\n            \"collectionFormat\": \"multi\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/pets\": {\n      \"get\": {\n        \"operationId\": \"PetService_ListPet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1PetList\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"pageSize\",\n            \"description\": \"每页条数，0 使用默认值.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"integer\",\n            \"format\": \"int32\"\n          },\n          {\n            \"name\": \"pageToken\",\n            \"description\": \"上一页返回的 next_page_token，为空表示第一页.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"filter\",\n            \"description\": \"过滤表达式，如：type = \\\"cat\\\" AND age \\u003e 2 AND owned = false.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"orderBy\",\n            \"description\": \"排序，如：created_at desc, age.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"showDeleted\",\n            \"description\": \"包含已删除的记录.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"boolean\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"post\": {\n        \"operationId\": \"PetService_CreatePet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/pets/{id}\": {\n      \"get\": {\n        \"operationId\": \"PetService_GetPet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"delete\": {\n        \"operationId\": \"PetService_DeletePet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"properties\": {}\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"etag\",\n            \"description\": \"为空时使用 If-Match 请求头.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"string\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/pets/{id}:undelete\": {\n      \"post\": {\n        \"operationId\": \"PetService_UndeletePet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1UndeletePetRequest\"\n            }\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    },\n    \"/v1/pets/{pet.id}\": {\n      \"put\": {\n        \"operationId\": \"PetService_UpdatePet\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"pet.id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          {\n            \"name\": \"updateMask\",\n            \"description\": \"需要更新的字段，为空时只更新非零值字段，\\\"*\\\" 更新全部字段.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"array\",\n            \"items\": {\n              \"type\": \"string\"\n            },\n            \"collectionFormat\": \"multi\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      },\n      \"patch\": {\n        \"operationId\": \"PetService_UpdatePet2\",\n        \"responses\": {\n          \"200\": {\n            \"description\": \"A successful response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          \"default\": {\n            \"description\": \"An unexpected error response.\",\n            \"schema\": {\n              \"$ref\": \"#/definitions/rpcStatus\"\n            }\n          }\n        },\n        \"parameters\": [\n          {\n            \"name\": \"pet.id\",\n            \"in\": \"path\",\n            \"required\": true,\n            \"type\": \"string\"\n          },\n          {\n            \"name\": \"body\",\n            \"in\": \"body\",\n            \"required\": true,\n            \"schema\": {\n              \"$ref\": \"#/definitions/v1Pet\"\n            }\n          },\n          {\n            \"name\": \"updateMask\",\n            \"description\": \"需要更新的字段，为空时只更新非零值字段，\\\"*\\\" 更新全部字段.\",\n            \"in\": \"query\",\n            \"required\": false,\n            \"type\": \"array\",\n            \"items\": {\n              \"type\": \"string\"\n            },\n            \"collectionFormat\": \"multi\"\n          }\n        ],\n        \"tags\": [\n          \"PetService\"\n        ]\n      }\n    }\n  },\n  \"definitions\": {\n    \"protobufAny\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"typeUrl\": {\n          \"type\": \"string\"\n        },\n        \"value\": {\n          \"type\": \"string\",\n          \"format\": \"byte\"\n        }\n      }\n    },\n    \"rpcStatus\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"code\": {\n          \"type\": \"integer\",\n          \"format\": \"int32\"\n        },\n        \"message\": {\n          \"type\": \"string\"\n        },\n        \"details\": {\n          \"type\": \"array\",\n          \"items\": {\n            \"$ref\": \"#/definitions/protobufAny\"\n          }\n        }\n      }\n    },\n    \"v1Id\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        }\n      }\n    },\n    \"v1Owner\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        },\n        \"createdAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"updatedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"name\": {\n          \"type\": \"string\"\n        },\n        \"sex\": {\n          \"type\": \"string\"\n        },\n        \"age\": {\n          \"type\": \"integer\",\n          \"format\": \"int64\"\n        },\n        \"phone\": {\n          \"type\": \"string\"\n        },\n        \"etag\": {\n          \"type\": \"string\",\n          \"title\": \"乐观锁，更新和删除时传回，也可以使用 If-Match 请求头\"\n        },\n        \"deletedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\",\n          \"title\": \"删除时间，未删除时为空\"\n        }\n      }\n    },\n    \"v1OwnerList\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"items\": {\n          \"type\": \"array\",\n          \"items\": {\n            \"$ref\": \"#/definitions/v1Owner\"\n          }\n        },\n        \"nextPageToken\": {\n          \"type\": \"string\",\n          \"title\": \"为空表示没有下一页\"\n        },\n        \"totalSize\": {\n          \"type\": \"integer\",\n          \"format\": \"int32\"\n        }\n      }\n    },\n    \"v1OwnerPet\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        },\n        \"createdAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"updatedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"ownerId\": {\n          \"type\": \"string\"\n        },\n        \"petId\": {\n          \"type\": \"string\"\n        }\n      }\n    },\n    \"v1Pet\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        },\n        \"createdAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"updatedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\"\n        },\n        \"name\": {\n          \"type\": \"string\"\n        },\n        \"type\": {\n          \"type\": \"string\"\n        },\n        \"sex\": {\n          \"type\": \"string\"\n        },\n        \"age\": {\n          \"type\": \"integer\",\n          \"format\": \"int64\"\n        },\n        \"owned\": {\n          \"type\": \"boolean\"\n        },\n        \"etag\": {\n          \"type\": \"string\",\n          \"title\": \"乐观锁，更新和删除时传回，也可以使用 If-Match 请求头\"\n        },\n        \"deletedAt\": {\n          \"type\": \"string\",\n          \"format\": \"date-time\",\n          \"title\": \"删除时间，未删除时为空\"\n        }\n      }\n    },\n    \"v1PetList\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"items\": {\n          \"type\": \"array\",\n          \"items\": {\n            \"$ref\": \"#/definitions/v1Pet\"\n          }\n        },\n        \"nextPageToken\": {\n          \"type\": \"string\",\n          \"title\": \"为空表示没有下一页\"\n        },\n        \"totalSize\": {\n          \"type\": \"integer\",\n          \"format\": \"int32\"\n        }\n      }\n    },\n    \"v1UndeleteOwnerRequest\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        },\n        \"etag\": {\n          \"type\": \"string\",\n          \"title\": \"可选，校验版本号\"\n        }\n      }\n    },\n    \"v1UndeletePetRequest\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"id\": {\n          \"type\": \"string\"\n        },\n        \"etag\": {\n          \"type\": \"string\",\n          \"title\": \"可选，校验版本号\"\n        }\n      }\n    }\n  }\n}\n",
+}
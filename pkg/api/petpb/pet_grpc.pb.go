@@ -19,16 +19,18 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PetServiceClient interface {
 	Ping(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Id, error)
-	ListPet(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PetList, error)
+	ListPet(ctx context.Context, in *ListPetRequest, opts ...grpc.CallOption) (*PetList, error)
 	GetPet(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Pet, error)
 	CreatePet(ctx context.Context, in *Pet, opts ...grpc.CallOption) (*Pet, error)
-	UpdatePet(ctx context.Context, in *Pet, opts ...grpc.CallOption) (*Pet, error)
-	DeletePet(ctx context.Context, in *Id, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	ListOwner(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*OwnerList, error)
+	UpdatePet(ctx context.Context, in *UpdatePetRequest, opts ...grpc.CallOption) (*Pet, error)
+	DeletePet(ctx context.Context, in *DeletePetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UndeletePet(ctx context.Context, in *UndeletePetRequest, opts ...grpc.CallOption) (*Pet, error)
+	ListOwner(ctx context.Context, in *ListOwnerRequest, opts ...grpc.CallOption) (*OwnerList, error)
 	GetOwner(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Owner, error)
 	CreateOwner(ctx context.Context, in *Owner, opts ...grpc.CallOption) (*Owner, error)
-	UpdateOwner(ctx context.Context, in *Owner, opts ...grpc.CallOption) (*Owner, error)
-	DeleteOwner(ctx context.Context, in *Id, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateOwner(ctx context.Context, in *UpdateOwnerRequest, opts ...grpc.CallOption) (*Owner, error)
+	DeleteOwner(ctx context.Context, in *DeleteOwnerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UndeleteOwner(ctx context.Context, in *UndeleteOwnerRequest, opts ...grpc.CallOption) (*Owner, error)
 	OwnPet(ctx context.Context, in *OwnerPet, opts ...grpc.CallOption) (*OwnerPet, error)
 	AbandonPet(ctx context.Context, in *OwnerPet, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
@@ -50,7 +52,7 @@ func (c *petServiceClient) Ping(ctx context.Context, in *Id, opts ...grpc.CallOp
 	return out, nil
 }
 
-func (c *petServiceClient) ListPet(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PetList, error) {
+func (c *petServiceClient) ListPet(ctx context.Context, in *ListPetRequest, opts ...grpc.CallOption) (*PetList, error) {
 	out := new(PetList)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/ListPet", in, out, opts...)
 	if err != nil {
@@ -77,7 +79,7 @@ func (c *petServiceClient) CreatePet(ctx context.Context, in *Pet, opts ...grpc.
 	return out, nil
 }
 
-func (c *petServiceClient) UpdatePet(ctx context.Context, in *Pet, opts ...grpc.CallOption) (*Pet, error) {
+func (c *petServiceClient) UpdatePet(ctx context.Context, in *UpdatePetRequest, opts ...grpc.CallOption) (*Pet, error) {
 	out := new(Pet)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/UpdatePet", in, out, opts...)
 	if err != nil {
@@ -86,7 +88,7 @@ func (c *petServiceClient) UpdatePet(ctx context.Context, in *Pet, opts ...grpc.
 	return out, nil
 }
 
-func (c *petServiceClient) DeletePet(ctx context.Context, in *Id, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *petServiceClient) DeletePet(ctx context.Context, in *DeletePetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/DeletePet", in, out, opts...)
 	if err != nil {
@@ -95,7 +97,16 @@ func (c *petServiceClient) DeletePet(ctx context.Context, in *Id, opts ...grpc.C
 	return out, nil
 }
 
-func (c *petServiceClient) ListOwner(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*OwnerList, error) {
+func (c *petServiceClient) UndeletePet(ctx context.Context, in *UndeletePetRequest, opts ...grpc.CallOption) (*Pet, error) {
+	out := new(Pet)
+	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/UndeletePet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *petServiceClient) ListOwner(ctx context.Context, in *ListOwnerRequest, opts ...grpc.CallOption) (*OwnerList, error) {
 	out := new(OwnerList)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/ListOwner", in, out, opts...)
 	if err != nil {
@@ -122,7 +133,7 @@ func (c *petServiceClient) CreateOwner(ctx context.Context, in *Owner, opts ...g
 	return out, nil
 }
 
-func (c *petServiceClient) UpdateOwner(ctx context.Context, in *Owner, opts ...grpc.CallOption) (*Owner, error) {
+func (c *petServiceClient) UpdateOwner(ctx context.Context, in *UpdateOwnerRequest, opts ...grpc.CallOption) (*Owner, error) {
 	out := new(Owner)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/UpdateOwner", in, out, opts...)
 	if err != nil {
@@ -131,7 +142,7 @@ func (c *petServiceClient) UpdateOwner(ctx context.Context, in *Owner, opts ...g
 	return out, nil
 }
 
-func (c *petServiceClient) DeleteOwner(ctx context.Context, in *Id, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *petServiceClient) DeleteOwner(ctx context.Context, in *DeleteOwnerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/DeleteOwner", in, out, opts...)
 	if err != nil {
@@ -140,6 +151,15 @@ func (c *petServiceClient) DeleteOwner(ctx context.Context, in *Id, opts ...grpc
 	return out, nil
 }
 
+func (c *petServiceClient) UndeleteOwner(ctx context.Context, in *UndeleteOwnerRequest, opts ...grpc.CallOption) (*Owner, error) {
+	out := new(Owner)
+	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/UndeleteOwner", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *petServiceClient) OwnPet(ctx context.Context, in *OwnerPet, opts ...grpc.CallOption) (*OwnerPet, error) {
 	out := new(OwnerPet)
 	err := c.cc.Invoke(ctx, "/pet.service.v1.PetService/OwnPet", in, out, opts...)
@@ -163,16 +183,18 @@ func (c *petServiceClient) AbandonPet(ctx context.Context, in *OwnerPet, opts ..
 // for forward compatibility
 type PetServiceServer interface {
 	Ping(context.Context, *Id) (*Id, error)
-	ListPet(context.Context, *emptypb.Empty) (*PetList, error)
+	ListPet(context.Context, *ListPetRequest) (*PetList, error)
 	GetPet(context.Context, *Id) (*Pet, error)
 	CreatePet(context.Context, *Pet) (*Pet, error)
-	UpdatePet(context.Context, *Pet) (*Pet, error)
-	DeletePet(context.Context, *Id) (*emptypb.Empty, error)
-	ListOwner(context.Context, *emptypb.Empty) (*OwnerList, error)
+	UpdatePet(context.Context, *UpdatePetRequest) (*Pet, error)
+	DeletePet(context.Context, *DeletePetRequest) (*emptypb.Empty, error)
+	UndeletePet(context.Context, *UndeletePetRequest) (*Pet, error)
+	ListOwner(context.Context, *ListOwnerRequest) (*OwnerList, error)
 	GetOwner(context.Context, *Id) (*Owner, error)
 	CreateOwner(context.Context, *Owner) (*Owner, error)
-	UpdateOwner(context.Context, *Owner) (*Owner, error)
-	DeleteOwner(context.Context, *Id) (*emptypb.Empty, error)
+	UpdateOwner(context.Context, *UpdateOwnerRequest) (*Owner, error)
+	DeleteOwner(context.Context, *DeleteOwnerRequest) (*emptypb.Empty, error)
+	UndeleteOwner(context.Context, *UndeleteOwnerRequest) (*Owner, error)
 	OwnPet(context.Context, *OwnerPet) (*OwnerPet, error)
 	AbandonPet(context.Context, *OwnerPet) (*emptypb.Empty, error)
 	mustEmbedUnimplementedPetServiceServer()
@@ -185,7 +207,7 @@ type UnimplementedPetServiceServer struct {
 func (UnimplementedPetServiceServer) Ping(context.Context, *Id) (*Id, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
-func (UnimplementedPetServiceServer) ListPet(context.Context, *emptypb.Empty) (*PetList, error) {
+func (UnimplementedPetServiceServer) ListPet(context.Context, *ListPetRequest) (*PetList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPet not implemented")
 }
 func (UnimplementedPetServiceServer) GetPet(context.Context, *Id) (*Pet, error) {
@@ -194,13 +216,16 @@ func (UnimplementedPetServiceServer) GetPet(context.Context, *Id) (*Pet, error)
 func (UnimplementedPetServiceServer) CreatePet(context.Context, *Pet) (*Pet, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreatePet not implemented")
 }
-func (UnimplementedPetServiceServer) UpdatePet(context.Context, *Pet) (*Pet, error) {
+func (UnimplementedPetServiceServer) UpdatePet(context.Context, *UpdatePetRequest) (*Pet, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePet not implemented")
 }
-func (UnimplementedPetServiceServer) DeletePet(context.Context, *Id) (*emptypb.Empty, error) {
+func (UnimplementedPetServiceServer) DeletePet(context.Context, *DeletePetRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeletePet not implemented")
 }
-func (UnimplementedPetServiceServer) ListOwner(context.Context, *emptypb.Empty) (*OwnerList, error) {
+func (UnimplementedPetServiceServer) UndeletePet(context.Context, *UndeletePetRequest) (*Pet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeletePet not implemented")
+}
+func (UnimplementedPetServiceServer) ListOwner(context.Context, *ListOwnerRequest) (*OwnerList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListOwner not implemented")
 }
 func (UnimplementedPetServiceServer) GetOwner(context.Context, *Id) (*Owner, error) {
@@ -209,12 +234,15 @@ func (UnimplementedPetServiceServer) GetOwner(context.Context, *Id) (*Owner, err
 func (UnimplementedPetServiceServer) CreateOwner(context.Context, *Owner) (*Owner, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateOwner not implemented")
 }
-func (UnimplementedPetServiceServer) UpdateOwner(context.Context, *Owner) (*Owner, error) {
+func (UnimplementedPetServiceServer) UpdateOwner(context.Context, *UpdateOwnerRequest) (*Owner, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateOwner not implemented")
 }
-func (UnimplementedPetServiceServer) DeleteOwner(context.Context, *Id) (*emptypb.Empty, error) {
+func (UnimplementedPetServiceServer) DeleteOwner(context.Context, *DeleteOwnerRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteOwner not implemented")
 }
+func (UnimplementedPetServiceServer) UndeleteOwner(context.Context, *UndeleteOwnerRequest) (*Owner, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteOwner not implemented")
+}
 func (UnimplementedPetServiceServer) OwnPet(context.Context, *OwnerPet) (*OwnerPet, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OwnPet not implemented")
 }
@@ -253,7 +281,7 @@ func _PetService_Ping_Handler(srv interface{}, ctx context.Context, dec func(int
 }
 
 func _PetService_ListPet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListPetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -265,7 +293,7 @@ func _PetService_ListPet_Handler(srv interface{}, ctx context.Context, dec func(
 		FullMethod: "/pet.service.v1.PetService/ListPet",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PetServiceServer).ListPet(ctx, req.(*emptypb.Empty))
+		return srv.(PetServiceServer).ListPet(ctx, req.(*ListPetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -307,7 +335,7 @@ func _PetService_CreatePet_Handler(srv interface{}, ctx context.Context, dec fun
 }
 
 func _PetService_UpdatePet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Pet)
+	in := new(UpdatePetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -319,13 +347,13 @@ func _PetService_UpdatePet_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: "/pet.service.v1.PetService/UpdatePet",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PetServiceServer).UpdatePet(ctx, req.(*Pet))
+		return srv.(PetServiceServer).UpdatePet(ctx, req.(*UpdatePetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _PetService_DeletePet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Id)
+	in := new(DeletePetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -337,13 +365,31 @@ func _PetService_DeletePet_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: "/pet.service.v1.PetService/DeletePet",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PetServiceServer).DeletePet(ctx, req.(*Id))
+		return srv.(PetServiceServer).DeletePet(ctx, req.(*DeletePetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_UndeletePet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeletePetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).UndeletePet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pet.service.v1.PetService/UndeletePet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).UndeletePet(ctx, req.(*UndeletePetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _PetService_ListOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListOwnerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -355,7 +401,7 @@ func _PetService_ListOwner_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: "/pet.service.v1.PetService/ListOwner",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PetServiceServer).ListOwner(ctx, req.(*emptypb.Empty))
+		return srv.(PetServiceServer).ListOwner(ctx, req.(*ListOwnerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -397,7 +443,7 @@ func _PetService_CreateOwner_Handler(srv interface{}, ctx context.Context, dec f
 }
 
 func _PetService_UpdateOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Owner)
+	in := new(UpdateOwnerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -409,13 +455,13 @@ func _PetService_UpdateOwner_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: "/pet.service.v1.PetService/UpdateOwner",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PetServiceServer).UpdateOwner(ctx, req.(*Owner))
+		return srv.(PetServiceServer).UpdateOwner(ctx, req.(*UpdateOwnerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _PetService_DeleteOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Id)
+	in := new(DeleteOwnerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -427,7 +473,25 @@ func _PetService_DeleteOwner_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: "/pet.service.v1.PetService/DeleteOwner",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PetServiceServer).DeleteOwner(ctx, req.(*Id))
+		return srv.(PetServiceServer).DeleteOwner(ctx, req.(*DeleteOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PetService_UndeleteOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PetServiceServer).UndeleteOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pet.service.v1.PetService/UndeleteOwner",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PetServiceServer).UndeleteOwner(ctx, req.(*UndeleteOwnerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -496,6 +560,10 @@ var _PetService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeletePet",
 			Handler:    _PetService_DeletePet_Handler,
 		},
+		{
+			MethodName: "UndeletePet",
+			Handler:    _PetService_UndeletePet_Handler,
+		},
 		{
 			MethodName: "ListOwner",
 			Handler:    _PetService_ListOwner_Handler,
@@ -516,6 +584,10 @@ var _PetService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteOwner",
 			Handler:    _PetService_DeleteOwner_Handler,
 		},
+		{
+			MethodName: "UndeleteOwner",
+			Handler:    _PetService_UndeleteOwner_Handler,
+		},
 		{
 			MethodName: "OwnPet",
 			Handler:    _PetService_OwnPet_Handler,
@@ -0,0 +1,189 @@
+// Package auth 校验 JWT bearer token，支持 HS256 共享密钥和 RS256 公钥（PEM 文件或 JWKS 文件）
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+)
+
+type Config struct {
+	SecretFile string   // HS256 共享密钥文件
+	KeyFiles   []string // RS256 公钥 PEM 文件
+	JwksFile   string   // RS256 JWKS 文件，按 token 的 kid 选择公钥
+	Issuer     string   // 不为空时校验 iss
+	Audience   string   // 不为空时校验 aud
+	// 不需要认证的 grpc 方法全名，如 /pet.service.v1.PetService/Ping，/* 结尾表示整个服务
+	PublicMethods []string
+}
+
+// 配置了任一密钥时开启认证
+func (c *Config) Enabled() bool {
+	return c.SecretFile != "" || len(c.KeyFiles) > 0 || c.JwksFile != ""
+}
+
+// 认证通过的调用方
+type Principal struct {
+	Subject string
+	Claims  jwt.MapClaims
+}
+
+type ctxPrincipalKey struct{}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxPrincipalKey{}, p)
+}
+
+// 未认证或公开方法没有带 token 时返回 false
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(ctxPrincipalKey{}).(*Principal)
+	return p, ok
+}
+
+type Authenticator struct {
+	secret   []byte
+	keys     []*rsaKey
+	issuer   string
+	audience string
+	public   map[string]bool
+	parser   *jwt.Parser
+}
+
+func New(cfg *Config) (*Authenticator, error) {
+	s := &Authenticator{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		public:   make(map[string]bool),
+	}
+
+	var methods []string
+	if cfg.SecretFile != "" {
+		secret, err := loadSecret(cfg.SecretFile)
+		if err != nil {
+			return nil, err
+		}
+		s.secret = secret
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	for _, v := range cfg.KeyFiles {
+		key, err := loadPublicKey(v)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = append(s.keys, key)
+	}
+	if cfg.JwksFile != "" {
+		keys, err := loadJwks(cfg.JwksFile)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = append(s.keys, keys...)
+	}
+	if len(s.keys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors2.New("no key configured for auth")
+	}
+	// 只接受配置了密钥的算法，避免 alg 为 none 或用公钥作为 HMAC 密钥
+	s.parser = jwt.NewParser(jwt.WithValidMethods(methods))
+
+	for _, v := range cfg.PublicMethods {
+		s.public[v] = true
+	}
+	return s, nil
+}
+
+// 公开方法不要求 token
+func (s *Authenticator) IsPublic(fullMethod string) bool {
+	if s.public[fullMethod] {
+		return true
+	}
+
+	if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+		return s.public[fullMethod[:i]+"/*"]
+	}
+	return false
+}
+
+// 校验 token，失败时返回 errcode.Err_unauthenticated
+func (s *Authenticator) Verify(token string) (*Principal, error) {
+	unverified, _, err := s.parser.ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return nil, errors2.Wrap(errcode.Err_unauthenticated, err.Error())
+	}
+	kid, _ := unverified.Header["kid"].(string)
+
+	var claims jwt.MapClaims
+	err = errors2.New("no key for the token")
+	for _, key := range s.candidates(unverified.Method.Alg(), kid) {
+		claims = jwt.MapClaims{}
+		// 算法由 parser 按 WithValidMethods 校验
+		_, err = s.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+			return key, nil
+		})
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors2.Wrap(errcode.Err_unauthenticated, err.Error())
+	}
+
+	// 解析时已校验 exp、nbf、iat，这里要求必须带 exp
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors2.Wrap(errcode.Err_unauthenticated, "token has no expiration")
+	}
+	if s.issuer != "" && !claims.VerifyIssuer(s.issuer, true) {
+		return nil, errors2.Wrap(errcode.Err_unauthenticated, "invalid issuer")
+	}
+	if s.audience != "" && !claims.VerifyAudience(s.audience, true) {
+		return nil, errors2.Wrap(errcode.Err_unauthenticated, "invalid audience")
+	}
+
+	sub, _ := claims["sub"].(string)
+	return &Principal{
+		Subject: sub,
+		Claims:  claims,
+	}, nil
+}
+
+// 按算法和 kid 选择密钥，不带 kid 的 token 依次尝试所有公钥
+func (s *Authenticator) candidates(alg, kid string) []interface{} {
+	switch alg {
+	case jwt.SigningMethodHS256.Alg():
+		if s.secret != nil {
+			return []interface{}{s.secret}
+		}
+	case jwt.SigningMethodRS256.Alg():
+		var r []interface{}
+		for _, v := range s.keys {
+			if kid == "" || v.kid == "" || v.kid == kid {
+				r = append(r, v.key)
+			}
+		}
+		return r
+	}
+	return nil
+}
+
+// 从 Authorization 的值中取出 bearer token
+func bearerToken(header string) (string, error) {
+	const prefix = "bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", errors2.Wrap(errcode.Err_unauthenticated, "bearer token required")
+	}
+
+	token := strings.TrimSpace(header[len(prefix):])
+	if token == "" {
+		return "", errors2.Wrap(errcode.Err_unauthenticated, "bearer token required")
+	}
+	return token, nil
+}
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	errors2 "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "auth")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	file := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(file, data, 0600))
+	return file
+}
+
+func claims(sub string, ttl time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": sub,
+		"exp": time.Now().Add(ttl).Unix(),
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, c jwt.MapClaims, key interface{}) string {
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func requireUnauthenticated(t *testing.T, err error) {
+	require.Error(t, err)
+	require.True(t, errors2.Is(err, errcode.Err_unauthenticated), err.Error())
+}
+
+func TestHS256(t *testing.T) {
+	dir := tempDir(t)
+	secret := []byte("0123456789abcdef")
+	a, err := New(&Config{
+		SecretFile: writeFile(t, dir, "secret", append(secret, '\n')),
+		Issuer:     "demo",
+	})
+	require.NoError(t, err)
+
+	c := claims("alice", time.Minute)
+	c["iss"] = "demo"
+	p, err := a.Verify(sign(t, jwt.SigningMethodHS256, "", c, secret))
+	require.NoError(t, err)
+	require.Equal(t, "alice", p.Subject)
+
+	// 过期
+	c = claims("alice", -time.Minute)
+	c["iss"] = "demo"
+	_, err = a.Verify(sign(t, jwt.SigningMethodHS256, "", c, secret))
+	requireUnauthenticated(t, err)
+
+	// 密钥错误
+	c = claims("alice", time.Minute)
+	c["iss"] = "demo"
+	_, err = a.Verify(sign(t, jwt.SigningMethodHS256, "", c, []byte("other")))
+	requireUnauthenticated(t, err)
+
+	// issuer 不匹配
+	_, err = a.Verify(sign(t, jwt.SigningMethodHS256, "", claims("alice", time.Minute), secret))
+	requireUnauthenticated(t, err)
+
+	// 没有 exp
+	_, err = a.Verify(sign(t, jwt.SigningMethodHS256, "", jwt.MapClaims{"sub": "alice", "iss": "demo"}, secret))
+	requireUnauthenticated(t, err)
+
+	// alg 为 none
+	c = claims("alice", time.Minute)
+	c["iss"] = "demo"
+	_, err = a.Verify(sign(t, jwt.SigningMethodNone, "", c, jwt.UnsafeAllowNoneSignatureType))
+	requireUnauthenticated(t, err)
+
+	_, err = a.Verify("not a token")
+	requireUnauthenticated(t, err)
+}
+
+func TestRS256(t *testing.T) {
+	dir := tempDir(t)
+	static, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&static.PublicKey)
+	require.NoError(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	set, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "EC", "kid": "ec"},
+			{
+				"kty": "RSA",
+				"kid": "k1",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(jwksKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(jwksKey.E)).Bytes()),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	a, err := New(&Config{
+		KeyFiles: []string{writeFile(t, dir, "key.pem", pemBytes)},
+		JwksFile: writeFile(t, dir, "jwks.json", set),
+		Audience: "pet",
+	})
+	require.NoError(t, err)
+
+	c := claims("bob", time.Minute)
+	c["aud"] = []string{"pet", "admin"}
+
+	// 静态公钥，不带 kid
+	p, err := a.Verify(sign(t, jwt.SigningMethodRS256, "", c, static))
+	require.NoError(t, err)
+	require.Equal(t, "bob", p.Subject)
+
+	// JWKS 按 kid 选择公钥
+	_, err = a.Verify(sign(t, jwt.SigningMethodRS256, "k1", c, jwksKey))
+	require.NoError(t, err)
+
+	// 未知 kid 的 token 只能由静态公钥校验
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = a.Verify(sign(t, jwt.SigningMethodRS256, "k2", c, other))
+	requireUnauthenticated(t, err)
+
+	// audience 不匹配
+	_, err = a.Verify(sign(t, jwt.SigningMethodRS256, "", claims("bob", time.Minute), static))
+	requireUnauthenticated(t, err)
+
+	// 用公钥作为 HMAC 密钥伪造
+	_, err = a.Verify(sign(t, jwt.SigningMethodHS256, "", c, pemBytes))
+	requireUnauthenticated(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{})
+	require.Error(t, err)
+
+	dir := tempDir(t)
+	_, err = New(&Config{SecretFile: writeFile(t, dir, "empty", []byte("\n"))})
+	require.Error(t, err)
+
+	_, err = New(&Config{JwksFile: writeFile(t, dir, "jwks.json", []byte(`{"keys":[]}`))})
+	require.Error(t, err)
+}
+
+func TestInterceptor(t *testing.T) {
+	dir := tempDir(t)
+	secret := []byte("0123456789abcdef")
+	a, err := New(&Config{
+		SecretFile:    writeFile(t, dir, "secret", secret),
+		PublicMethods: []string{"/pet.service.v1.PetService/Ping", "/grpc.health.v1.Health/*"},
+	})
+	require.NoError(t, err)
+
+	interceptor := a.UnaryServerInterceptor()
+	call := func(method, header string) (*Principal, error) {
+		ctx := context.Background()
+		if header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", header))
+		}
+
+		var p *Principal
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			p, _ = FromContext(ctx)
+			return nil, nil
+		})
+		return p, err
+	}
+
+	token := sign(t, jwt.SigningMethodHS256, "", claims("alice", time.Minute), secret)
+
+	// 公开方法
+	p, err := call("/pet.service.v1.PetService/Ping", "")
+	require.NoError(t, err)
+	require.Nil(t, p)
+	_, err = call("/grpc.health.v1.Health/Check", "Bearer invalid")
+	require.NoError(t, err)
+	p, err = call("/pet.service.v1.PetService/Ping", "Bearer "+token)
+	require.NoError(t, err)
+	require.Equal(t, "alice", p.Subject)
+
+	// 需要认证
+	for _, header := range []string{"", "Basic abc", "Bearer ", "Bearer invalid"} {
+		_, err = call("/pet.service.v1.PetService/GetPet", header)
+		require.Equal(t, codes.Unauthenticated, status.Code(err), header)
+	}
+
+	p, err = call("/pet.service.v1.PetService/GetPet", "bearer "+token)
+	require.NoError(t, err)
+	require.Equal(t, "alice", p.Subject)
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	log "github.com/win5do/go-lib/logx"
+)
+
+// 校验 metadata 中的 authorization，通过后把 Principal 放入 ctx
+//
+// 公开方法不要求 token，带了有效 token 时同样放入 Principal，无效 token 视为未带
+func (s *Authenticator) Authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	p, err := s.fromMetadata(ctx)
+	if err != nil {
+		if s.IsPublic(fullMethod) {
+			return ctx, nil
+		}
+
+		log.Debugf("auth %s failed: %v", fullMethod, err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return NewContext(ctx, p), nil
+}
+
+func (s *Authenticator) fromMetadata(ctx context.Context) (*Principal, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			header = v[0]
+		}
+	}
+
+	token, err := bearerToken(header)
+	if err != nil {
+		return nil, err
+	}
+	return s.Verify(token)
+}
+
+func (s *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := s.Authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (s *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := s.Authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
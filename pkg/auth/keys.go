@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/go-lib/errx"
+)
+
+// RS256 公钥，kid 为空时可以校验任意不带 kid 的 token
+type rsaKey struct {
+	kid string
+	key *rsa.PublicKey
+}
+
+func loadSecret(file string) ([]byte, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	// 去掉文件末尾的换行
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, errors2.Errorf("empty secret: %s", file)
+	}
+	return b, nil
+}
+
+// PEM 格式的公钥或证书
+func loadPublicKey(file string) (*rsaKey, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	key, err := jwt.ParseRSAPublicKeyFromPEM(b)
+	if err != nil {
+		return nil, errors2.Wrapf(err, "parse public key: %s", file)
+	}
+	return &rsaKey{key: key}, nil
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// 只加载用于签名的 RSA 公钥，其他类型的 key 忽略
+func loadJwks(file string) ([]*rsaKey, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, errors2.Wrapf(err, "parse jwks: %s", file)
+	}
+
+	var r []*rsaKey
+	for _, v := range set.Keys {
+		if v.Kty != "RSA" || (v.Use != "" && v.Use != "sig") || (v.Alg != "" && v.Alg != "RS256") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(v.N)
+		if err != nil {
+			return nil, errors2.Wrapf(err, "decode n of key %q", v.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(v.E)
+		if err != nil {
+			return nil, errors2.Wrapf(err, "decode e of key %q", v.Kid)
+		}
+
+		r = append(r, &rsaKey{
+			kid: v.Kid,
+			key: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			},
+		})
+	}
+
+	if len(r) == 0 {
+		return nil, errors2.Errorf("no rsa signing key in jwks: %s", file)
+	}
+	return r, nil
+}
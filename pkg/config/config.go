@@ -2,8 +2,10 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
+	errors2 "github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
 
@@ -11,6 +13,9 @@ import (
 
 	"github.com/win5do/go-lib/errx"
 
+	"github.com/win5do/golang-microservice-demo/pkg/auth"
+	"github.com/win5do/golang-microservice-demo/pkg/health"
+	"github.com/win5do/golang-microservice-demo/pkg/metrics"
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 
 	"github.com/win5do/golang-microservice-demo/pkg/config/util"
@@ -18,19 +23,64 @@ import (
 
 var globalConfg *Config
 
+// gateway 调用 grpc 服务的方式
+const (
+	GatewayDial      = "dial"      // 通过本机 grpc 连接转发
+	GatewayInProcess = "inprocess" // 进程内直接调用，不经过 grpc 拦截器，认证在 gateway 中处理
+)
+
+// 存储实现
+const (
+	StorageDb     = "db"
+	StorageMemory = "memory" // 纯内存，重启后数据丢失，用于本地开发和测试
+)
+
 type Config struct {
 	AppName         string
 	HttpPort        string
 	GrpcGatewayPort string
 	GrpcPort        string
+	// gateway 调用 grpc 服务的方式，GatewayDial 或 GatewayInProcess
+	GatewayMode string
+	// grpc 和 gateway 共用 GrpcPort，按请求协议分发，此时 GrpcGatewayPort 不生效
+	SinglePort bool
+
+	// 注册 grpc server reflection，用于 grpcurl 等工具调试
+	GrpcReflection bool
+	// /metrics 单独监听的端口，为空时使用 HttpPort
+	MetricsPort string
+	// 指标名前缀，go runtime 和进程指标除外
+	MetricsNamespace string
 
 	// https
 	TlsCert string
 	TlsKey  string
 
+	// JWT 认证，未配置密钥时不开启
+	Auth auth.Config
+	// 开启认证时由 InitConfig 设置
+	Authenticator *auth.Authenticator
+
 	Debug bool // debug log
 
+	Storage string
+
+	// 启动时执行的种子数据目录，为空不执行
+	SeedDir string
+
+	// 多副本选举 leader 执行后台任务，只对 db 存储有效
+	LeaderElection bool
+
+	// 允许通过 admin 接口强制释放锁，释放后原持有者可能仍在执行，默认关闭
+	AdminForceUnlock bool
+
+	// 软删除记录的保留时间，超过后永久删除，0 表示不清理
+	PurgeRetention time.Duration
+	PurgeInterval  time.Duration
+
 	dbcore.DBConfig
+	// 连接成功后设置，内存存储时为空
+	DB *dbcore.DB
 
 	Ctx    context.Context
 	Cancel context.CancelFunc
@@ -41,6 +91,16 @@ type Config struct {
 		JAEGER_AGENT_PORT
 	*/
 	Tracer opentracing.Tracer
+
+	Metrics *metrics.Metrics
+	Health  *health.Checker
+
+	// 定期更新 grpc 健康状态的间隔
+	HealthCheckInterval time.Duration
+	// 收到退出信号后先设为不可用，等待负载均衡摘除流量后再停止服务
+	ShutdownDelay time.Duration
+	// 停止 grpc、gateway、http 等服务的总超时，超时后强制关闭连接
+	ShutdownTimeout time.Duration
 }
 
 func DefaultConfig() *Config {
@@ -60,12 +120,56 @@ func SetFlags(flagSet *pflag.FlagSet, cfg *Config) {
 	flagSet.StringVar(&cfg.HttpPort, "http-port", "9010", "")
 	flagSet.StringVar(&cfg.GrpcPort, "grpc-port", "9020", "")
 	flagSet.StringVar(&cfg.GrpcGatewayPort, "grpc-gateway-port", "9030", "")
+	flagSet.StringVar(&cfg.GatewayMode, "gateway-mode", GatewayDial, "how gateway calls grpc services: dial or inprocess")
+	flagSet.BoolVar(&cfg.SinglePort, "single-port", false, "serve grpc and gateway on the grpc port")
+	flagSet.BoolVar(&cfg.GrpcReflection, "grpc-reflection", false, "enable grpc server reflection for tools like grpcurl")
+	flagSet.StringVar(&cfg.MetricsPort, "metrics-port", "", "serve /metrics on a separate port, default on http port")
+	flagSet.StringVar(&cfg.MetricsNamespace, "metrics-namespace", "", "prefix of metric names")
+	flagSet.DurationVar(&cfg.HealthCheckInterval, "health-check-interval", 5*time.Second, "interval of refreshing grpc health status")
+	flagSet.DurationVar(&cfg.ShutdownDelay, "shutdown-delay", 0, "wait after reporting not serving before stopping servers, e.g. longer than the readiness probe period")
+	flagSet.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", 10*time.Second, "max time to drain in-flight requests when stopping servers")
 	flagSet.StringVar(&cfg.TlsCert, "tls-cert", "", "")
 	flagSet.StringVar(&cfg.TlsKey, "tls-key", "", "")
+	flagSet.StringVar(&cfg.Auth.SecretFile, "auth-hs256-secret-file", "", "file of the HS256 shared secret, enables jwt auth")
+	flagSet.StringSliceVar(&cfg.Auth.KeyFiles, "auth-rs256-key-file", nil, "PEM file of a RS256 public key, can be repeated, enables jwt auth")
+	flagSet.StringVar(&cfg.Auth.JwksFile, "auth-jwks-file", "", "JWKS file of RS256 public keys selected by kid, enables jwt auth")
+	flagSet.StringVar(&cfg.Auth.Issuer, "auth-issuer", "", "required iss claim if set")
+	flagSet.StringVar(&cfg.Auth.Audience, "auth-audience", "", "required aud claim if set")
+	flagSet.StringSliceVar(&cfg.Auth.PublicMethods, "auth-public-methods", []string{
+		"/pet.service.v1.PetService/Ping",
+		"/grpc.health.v1.Health/*",
+	}, "grpc methods allowed without token, /* suffix matches the whole service")
+	flagSet.StringVar(&cfg.Storage, "storage", StorageDb, "storage backend: db or memory")
+	flagSet.StringVar(&cfg.Driver, "db-driver", dbcore.DriverMysql, "db driver: mysql, postgres or sqlite")
 	flagSet.StringVar(&cfg.DSN, "db-dsn", "root:123456@(127.0.0.1:3306)/go-demo", "")
+	flagSet.StringSliceVar(&cfg.ReplicaDSNs, "db-replica-dsn", nil, "read replica dsn, can be repeated, reads outside transactions go to replicas")
+	flagSet.DurationVar(&cfg.ConnectTimeout, "db-connect-timeout", time.Minute, "keep retrying to connect db within the timeout, 0 to disable retry")
+	flagSet.DurationVar(&cfg.RetryInterval, "db-retry-interval", time.Second, "initial interval of db connect retry, doubled on each failure")
+	flagSet.DurationVar(&cfg.RetryMaxInterval, "db-retry-max-interval", 30*time.Second, "")
+	flagSet.IntVar(&cfg.TxMaxRetries, "db-tx-retries", 3, "retry times of transactions aborted by deadlock or serialization failure")
+	flagSet.DurationVar(&cfg.SlowThreshold, "db-slow-threshold", 200*time.Millisecond, "log queries slower than the threshold, 0 to disable")
+	flagSet.DurationVar(&cfg.MetricsInterval, "db-metrics-interval", 15*time.Second, "interval of exporting db pool stats to prometheus")
+	flagSet.BoolVar(&cfg.AutoMigrate, "auto-migrate", true, "run database migrations on startup")
+	flagSet.StringVar(&cfg.SeedDir, "seed-dir", "", "directory of yaml/json seed files applied on startup")
+	flagSet.BoolVar(&cfg.LeaderElection, "leader-election", true, "run background jobs only on the elected leader")
+	flagSet.BoolVar(&cfg.AdminForceUnlock, "admin-force-unlock", false, "allow force releasing locks through the admin api")
+	flagSet.DurationVar(&cfg.PurgeRetention, "purge-retention", 30*24*time.Hour, "retention of soft deleted records, 0 to disable purge")
+	flagSet.DurationVar(&cfg.PurgeInterval, "purge-interval", time.Hour, "")
 }
 
 func InitConfig(cfg *Config) error {
+	switch cfg.Storage {
+	case StorageDb, StorageMemory:
+	default:
+		return errors2.Errorf("unknown storage: %s", cfg.Storage)
+	}
+
+	switch cfg.GatewayMode {
+	case GatewayDial, GatewayInProcess:
+	default:
+		return errors2.Errorf("unknown gateway mode: %s", cfg.GatewayMode)
+	}
+
 	var level zapcore.Level
 	if cfg.Debug {
 		level = zapcore.DebugLevel
@@ -76,12 +180,28 @@ func InitConfig(cfg *Config) error {
 
 	log.SetLogger(log.NewLogger(level))
 
+	cfg.Health = health.NewChecker()
+
 	// jaeger
 	err := SetupTrace(cfg.Ctx, cfg)
 	if err != nil {
 		return errx.WithStackOnce(err)
 	}
 
+	cfg.Metrics, err = metrics.New(cfg.MetricsNamespace)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Auth.Enabled() {
+		cfg.Authenticator, err = auth.New(&cfg.Auth)
+		if err != nil {
+			return err
+		}
+	} else {
+		log.Info("jwt auth disabled")
+	}
+
 	globalConfg = cfg
 	log.Debugf("cfg: %+v", cfg)
 	return nil
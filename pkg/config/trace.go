@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/opentracing/opentracing-go"
+	errors2 "github.com/pkg/errors"
 	"github.com/uber/jaeger-client-go"
 	jaegercfg "github.com/uber/jaeger-client-go/config"
 	jaegerzap "github.com/uber/jaeger-client-go/log/zap"
@@ -59,6 +60,14 @@ func SetupTrace(ctx context.Context, cfg *Config) error {
 		return errx.WithStackOnce(err)
 	}
 
+	// 配置了 jaeger 时检查 tracer 已注册
+	cfg.Health.Register("tracer", func(ctx context.Context) error {
+		if !opentracing.IsGlobalTracerRegistered() {
+			return errors2.New("global tracer not registered")
+		}
+		return nil
+	})
+
 	wg := util.GetWaitGroupInCtx(ctx)
 	wg.Add(1)
 
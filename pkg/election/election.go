@@ -0,0 +1,142 @@
+// Package election 基于 dbcore 分布式锁的 leader 选举，多副本中只有 leader 执行后台任务
+package election
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+const DefaultName = "leader"
+
+type Callbacks struct {
+	// 成为 leader 后调用，ctx 在失去 leader 或退出时取消，返回后才会释放锁
+	OnStartedLeading func(ctx context.Context)
+	// 失去 leader 后调用
+	OnStoppedLeading func()
+}
+
+type Elector struct {
+	name      string
+	identity  string
+	locker    dbcore.Locker
+	callbacks Callbacks
+
+	mu       sync.RWMutex
+	isLeader bool
+	since    time.Time
+}
+
+// locker 使用 name 作为 action，identity 作为 holder
+func New(name, identity string, locker dbcore.Locker, callbacks Callbacks) *Elector {
+	return &Elector{
+		name:      name,
+		identity:  identity,
+		locker:    locker,
+		callbacks: callbacks,
+	}
+}
+
+func NewDbElector(db *dbcore.DB, identity string, lease time.Duration, callbacks Callbacks) *Elector {
+	return New(DefaultName, identity, dbcore.NewLockDb(db, DefaultName, identity, lease), callbacks)
+}
+
+func (s *Elector) Name() string {
+	return s.name
+}
+
+func (s *Elector) Identity() string {
+	return s.identity
+}
+
+func (s *Elector) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isLeader
+}
+
+// 成为 leader 的时间，不是 leader 时为零值
+func (s *Elector) LeaderSince() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.since
+}
+
+func (s *Elector) setLeader(isLeader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.isLeader = isLeader
+	if isLeader {
+		s.since = time.Now()
+	} else {
+		s.since = time.Time{}
+	}
+}
+
+// 持续竞选直到 ctx 取消，退出时主动释放锁，其他副本无需等待租约过期
+func (s *Elector) Run(ctx context.Context) {
+	wg := util.GetWaitGroupInCtx(ctx)
+	wg.Add(1)
+	defer wg.Done()
+
+	for {
+		err := s.locker.LockContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Info("election stopped")
+				return
+			}
+
+			log.Errorf("campaign err: %+v", err)
+			select {
+			case <-time.After(time.Second):
+				continue
+			case <-ctx.Done():
+				log.Info("election stopped")
+				return
+			}
+		}
+
+		s.lead(ctx)
+
+		if ctx.Err() != nil {
+			log.Info("election stopped")
+			return
+		}
+	}
+}
+
+func (s *Elector) lead(ctx context.Context) {
+	log.Infof("%s started leading: %s", s.identity, s.name)
+	s.setLeader(true)
+
+	leadCtx, cancel := dbcore.LeaseContext(ctx, s.locker)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if s.callbacks.OnStartedLeading != nil {
+			s.callbacks.OnStartedLeading(leadCtx)
+		}
+	}()
+
+	// 回调提前返回不影响 leader 身份，直到租约丢失或退出
+	<-leadCtx.Done()
+	<-done
+
+	s.setLeader(false)
+	if err := s.locker.UnLock(); err != nil {
+		log.Errorf("release leader lock err: %+v", err)
+	}
+
+	log.Infof("%s stopped leading: %s", s.identity, s.name)
+	if s.callbacks.OnStoppedLeading != nil {
+		s.callbacks.OnStoppedLeading()
+	}
+}
@@ -0,0 +1,139 @@
+package election
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+)
+
+// 第一次获取立即成功，之后阻塞直到 ctx 取消
+type fakeLocker struct {
+	mu       sync.Mutex
+	locked   bool
+	acquired int
+	unlocked int
+	lostCh   chan struct{}
+}
+
+func (s *fakeLocker) Lock() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked || s.acquired > 0 {
+		return false, nil
+	}
+	s.locked = true
+	s.acquired++
+	s.lostCh = make(chan struct{})
+	return true, nil
+}
+
+func (s *fakeLocker) LockContext(ctx context.Context) error {
+	ok, _ := s.Lock()
+	if ok {
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *fakeLocker) TryLockFor(timeout time.Duration) (bool, error) {
+	return s.Lock()
+}
+
+func (s *fakeLocker) UnLock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked {
+		s.locked = false
+		s.unlocked++
+		close(s.lostCh)
+	}
+	return nil
+}
+
+func (s *fakeLocker) Token() int64 {
+	return 1
+}
+
+func (s *fakeLocker) Lost() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lostCh
+}
+
+// 模拟租约丢失
+func (s *fakeLocker) lose() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locked = false
+	close(s.lostCh)
+}
+
+func TestElectorHandover(t *testing.T) {
+	locker := &fakeLocker{}
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+
+	e := New("test", "a", locker, Callbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		},
+		OnStoppedLeading: func() {
+			close(stopped)
+		},
+	})
+
+	ctx, cancel := util.NewWaitGroupCtx()
+	go e.Run(ctx)
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("should start leading")
+	}
+	require.True(t, e.IsLeader())
+	require.False(t, e.LeaderSince().IsZero())
+
+	cancel()
+	util.GetWaitGroupInCtx(ctx).Wait()
+
+	<-stopped
+	require.False(t, e.IsLeader())
+	require.Equal(t, 1, locker.unlocked)
+}
+
+func TestElectorLeaseLost(t *testing.T) {
+	locker := &fakeLocker{}
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+
+	e := New("test", "a", locker, Callbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		},
+		OnStoppedLeading: func() {
+			close(stopped)
+		},
+	})
+
+	ctx, cancel := util.NewWaitGroupCtx()
+	defer cancel()
+	go e.Run(ctx)
+
+	<-started
+	locker.lose()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("should stop leading")
+	}
+	require.False(t, e.IsLeader())
+}
@@ -0,0 +1,211 @@
+// Package health 就绪检查，同时提供 grpc.health.v1 和 http 的 /healthz、/readyz
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+)
+
+// 单个检查的超时
+const checkTimeout = 3 * time.Second
+
+// 返回 nil 表示正常
+type CheckFunc func(ctx context.Context) error
+
+type Result struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// 检查注册表
+//
+// grpc 的健康状态由 Run 定期更新，/readyz 每次请求实时检查。
+// 开始退出时调用 Shutdown，之后始终返回 NOT_SERVING，让负载均衡先摘除流量。
+type Checker struct {
+	grpc *health.Server
+
+	mu       sync.Mutex
+	checks   []*check
+	services []string // grpc 服务名，与整体状态（空字符串）一起更新
+	shutdown bool
+}
+
+func NewChecker() *Checker {
+	s := &Checker{
+		grpc: health.NewServer(),
+	}
+	// 第一次检查前不接收流量
+	s.grpc.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return s
+}
+
+// 同名检查会被替换
+func (s *Checker) Register(name string, fn CheckFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.checks {
+		if v.name == name {
+			v.fn = fn
+			return
+		}
+	}
+	s.checks = append(s.checks, &check{name: name, fn: fn})
+}
+
+// 注册到 grpc server 的 health 服务
+func (s *Checker) GrpcServer() healthpb.HealthServer {
+	return s.grpc
+}
+
+// 除整体状态外，同时更新这些服务的状态
+func (s *Checker) AddService(names ...string) {
+	s.mu.Lock()
+	s.services = append(s.services, names...)
+	s.mu.Unlock()
+
+	s.update(context.Background())
+}
+
+// 并发执行所有检查，全部通过时 ok 为 true，结果按注册顺序返回
+func (s *Checker) Check(ctx context.Context) (results []*Result, ok bool) {
+	s.mu.Lock()
+	checks := append([]*check{}, s.checks...)
+	s.mu.Unlock()
+
+	results = make([]*Result, len(checks))
+	wg := &sync.WaitGroup{}
+	for i, v := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			results[i] = &Result{Name: c.name}
+			if err := c.fn(ctx); err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, v)
+	}
+	wg.Wait()
+
+	ok = true
+	for _, v := range results {
+		if v.Error != "" {
+			ok = false
+		}
+	}
+	return results, ok
+}
+
+func (s *Checker) isShutdown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shutdown
+}
+
+// 定期检查并更新 grpc 健康状态，直到 ctx 取消
+func (s *Checker) Run(ctx context.Context, interval time.Duration) {
+	wg := util.GetWaitGroupInCtx(ctx)
+	wg.Add(1)
+	defer wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.update(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Checker) update(ctx context.Context) {
+	if s.isShutdown() {
+		return
+	}
+
+	results, ok := s.Check(ctx)
+	status := healthpb.HealthCheckResponse_SERVING
+	if !ok {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+		for _, v := range results {
+			if v.Error != "" {
+				log.Errorf("health check %s failed: %s", v.Name, v.Error)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	services := append([]string{""}, s.services...)
+	s.mu.Unlock()
+
+	// Shutdown 之后 grpc health server 忽略状态更新
+	for _, v := range services {
+		s.grpc.SetServingStatus(v, status)
+	}
+}
+
+// 开始退出，之后 grpc 和 /readyz 都返回不可用
+func (s *Checker) Shutdown() {
+	s.mu.Lock()
+	s.shutdown = true
+	s.mu.Unlock()
+
+	s.grpc.Shutdown()
+	log.Info("health set to not serving")
+}
+
+// 存活检查，进程能响应即可，不依赖外部服务，避免数据库故障时被反复重启
+func (s *Checker) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok"))
+}
+
+// 就绪检查，任一检查失败或正在退出时返回 503
+func (s *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	resp := struct {
+		Status string    `json:"status"`
+		Checks []*Result `json:"checks,omitempty"`
+	}{
+		Status: "ok",
+	}
+	code := http.StatusOK
+
+	if s.isShutdown() {
+		resp.Status = "shutting down"
+		code = http.StatusServiceUnavailable
+	} else {
+		var ok bool
+		resp.Checks, ok = s.Check(r.Context())
+		if !ok {
+			resp.Status = "not ready"
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
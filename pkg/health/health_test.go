@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+)
+
+func grpcStatus(t *testing.T, s *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := s.GrpcServer().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func readyz(t *testing.T, s *Checker) (int, string) {
+	w := httptest.NewRecorder()
+	s.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var resp struct {
+		Status string
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return w.Code, resp.Status
+}
+
+func TestChecker(t *testing.T) {
+	s := NewChecker()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, grpcStatus(t, s, ""))
+
+	mu := sync.Mutex{}
+	var dbErr error
+	setDbErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dbErr = err
+	}
+	s.Register("db", func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return dbErr
+	})
+	s.Register("other", func(ctx context.Context) error {
+		return nil
+	})
+
+	ctx, cancel := util.NewWaitGroupCtx()
+	defer func() {
+		cancel()
+		util.GetWaitGroupInCtx(ctx).Wait()
+	}()
+	go s.Run(ctx, 10*time.Millisecond)
+	s.AddService("pet")
+
+	require.Eventually(t, func() bool {
+		return grpcStatus(t, s, "") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, grpcStatus(t, s, "pet"))
+
+	code, _ := readyz(t, s)
+	require.Equal(t, http.StatusOK, code)
+
+	// 检查失败
+	setDbErr(errors2.New("connection refused"))
+	results, ok := s.Check(context.Background())
+	require.False(t, ok)
+	require.Equal(t, "db", results[0].Name)
+	require.Equal(t, "connection refused", results[0].Error)
+	require.Empty(t, results[1].Error)
+
+	code, status := readyz(t, s)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "not ready", status)
+
+	require.Eventually(t, func() bool {
+		return grpcStatus(t, s, "pet") == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 10*time.Millisecond)
+
+	// 退出时检查通过也不可用
+	setDbErr(nil)
+	s.Shutdown()
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, grpcStatus(t, s, ""))
+
+	code, status = readyz(t, s)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "shutting down", status)
+
+	// 存活检查不受影响
+	w := httptest.NewRecorder()
+	s.Healthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
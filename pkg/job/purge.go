@@ -0,0 +1,23 @@
+package job
+
+import (
+	"context"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config"
+	petsvc "github.com/win5do/golang-microservice-demo/pkg/service/pet"
+)
+
+// 定时清理软删除超过保留期的记录，未开启时返回 nil
+func PurgeJob(cfg *config.Config, svc *petsvc.PetService) *Job {
+	if cfg.PurgeRetention <= 0 || cfg.PurgeInterval <= 0 {
+		return nil
+	}
+
+	return &Job{
+		Name: "purge",
+		Spec: "@every " + cfg.PurgeInterval.String(),
+		Run: func(ctx context.Context) error {
+			return svc.Purge(ctx, cfg.PurgeRetention)
+		},
+	}
+}
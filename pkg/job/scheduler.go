@@ -0,0 +1,337 @@
+package job
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	jobmodel "github.com/win5do/golang-microservice-demo/pkg/model/job"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+type Job struct {
+	Name string
+	// 标准 5 段 cron 表达式，也支持 @hourly、@every 1h 等
+	Spec string
+	// 单次执行超时，0 表示不限制
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+type JobStatus struct {
+	Name    string
+	Spec    string
+	Next    time.Time // 本副本下次调度时间，未在调度时为零值
+	Running bool      // 本副本正在执行
+	LastRun *jobmodel.JobRun
+}
+
+type entry struct {
+	job      *Job
+	schedule cron.Schedule
+	next     time.Time
+	running  bool
+}
+
+// 定时任务调度
+//
+// 每次执行前获取以任务名区分的分布式锁，多副本同时调度时只有一个执行，执行记录保存在 IJobRunDb。
+// 同一个调度时间点只执行一次，@every 按间隔对齐到整点，各副本的时间点一致。
+type Scheduler struct {
+	ctx       context.Context // 手动触发的任务在后台执行，随服务退出取消
+	identity  string
+	runs      jobmodel.IJobRunDb
+	newLocker func(action string) dbcore.Locker // 为空时不加锁，用于单副本的内存存储
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func NewScheduler(ctx context.Context, identity string, runs jobmodel.IJobRunDb, newLocker func(action string) dbcore.Locker) *Scheduler {
+	return &Scheduler{
+		ctx:       ctx,
+		identity:  identity,
+		runs:      runs,
+		newLocker: newLocker,
+		entries:   map[string]*entry{},
+	}
+}
+
+// 基于 db 锁，锁的 holder 为 identity，identity 相同的副本相互重入，不会互斥
+func NewDbLocker(db *dbcore.DB, identity string) func(action string) dbcore.Locker {
+	return func(action string) dbcore.Locker {
+		return dbcore.NewLockDb(db, action, identity, dbcore.DefaultLeaseAge)
+	}
+}
+
+func (s *Scheduler) Register(job *Job) error {
+	schedule, err := cron.ParseStandard(job.Spec)
+	if err != nil {
+		return errors2.Wrapf(err, "job %s spec: %s", job.Name, job.Spec)
+	}
+
+	// @every 默认从当前时间开始计算，各副本的调度时间点不同，执行记录无法去重
+	if v, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		schedule = alignedSchedule(v.Delay)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[job.Name]; ok {
+		return errors2.Errorf("duplicate job: %s", job.Name)
+	}
+
+	s.entries[job.Name] = &entry{
+		job:      job,
+		schedule: schedule,
+	}
+	return nil
+}
+
+// 按间隔对齐的固定间隔调度，如 @every 1h 在每个整点执行
+type alignedSchedule time.Duration
+
+func (s alignedSchedule) Next(t time.Time) time.Time {
+	d := time.Duration(s)
+	return t.Truncate(d).Add(d)
+}
+
+// 调度所有任务直到 ctx 取消，等待执行中的任务退出后返回
+func (s *Scheduler) Run(ctx context.Context) {
+	wg := util.GetWaitGroupInCtx(ctx)
+	wg.Add(1)
+	defer wg.Done()
+
+	s.mu.Lock()
+	entries := make([]*entry, 0, len(s.entries))
+	for _, v := range s.entries {
+		entries = append(entries, v)
+	}
+	s.mu.Unlock()
+
+	jobWg := &sync.WaitGroup{}
+	for _, v := range entries {
+		jobWg.Add(1)
+		go func(e *entry) {
+			defer jobWg.Done()
+			s.loop(ctx, e)
+		}(v)
+	}
+
+	jobWg.Wait()
+	log.Info("scheduler stopped")
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.setNext(e, time.Time{})
+
+	for {
+		next := e.schedule.Next(time.Now())
+		s.setNext(e, next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		run, locker, err := s.begin(ctx, e, &next)
+		if err != nil {
+			if errors2.Is(err, errcode.Err_conflict) {
+				log.Debugf("skip job %s: %s", e.job.Name, err)
+			} else {
+				log.Errorf("start job %s err: %+v", e.job.Name, err)
+			}
+			continue
+		}
+
+		s.execute(ctx, e, run, locker)
+	}
+}
+
+// 手动触发，在后台执行，任务正在执行时返回 errcode.Err_conflict
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*jobmodel.JobRun, error) {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, errors2.Wrapf(errcode.Err_not_found, "job: %s", name)
+	}
+
+	run, locker, err := s.begin(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 拷贝一份返回，执行结束时会修改 run
+	r := *run
+
+	wg := util.GetWaitGroupInCtx(s.ctx)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.execute(s.ctx, e, run, locker)
+	}()
+
+	return &r, nil
+}
+
+// 获取锁并记录开始，本副本或其他副本正在执行，或 scheduledAt 已经执行过时返回 errcode.Err_conflict
+// scheduledAt 为空表示手动触发
+func (s *Scheduler) begin(ctx context.Context, e *entry, scheduledAt *time.Time) (*jobmodel.JobRun, dbcore.Locker, error) {
+	// 分布式锁的 holder 相同时会重入，本副本内先在本地互斥
+	s.mu.Lock()
+	running := e.running
+	e.running = true
+	s.mu.Unlock()
+	if running {
+		return nil, nil, errors2.Wrapf(errcode.Err_conflict, "job %s is running", e.job.Name)
+	}
+
+	run, locker, err := s.acquire(ctx, e, scheduledAt)
+	if err != nil {
+		s.setRunning(e, false)
+		return nil, nil, err
+	}
+
+	return run, locker, nil
+}
+
+// 锁只保证同一时间只有一个执行，稍晚到达同一个时间点的副本在锁释放后由执行记录的唯一索引拒绝
+func (s *Scheduler) acquire(ctx context.Context, e *entry, scheduledAt *time.Time) (*jobmodel.JobRun, dbcore.Locker, error) {
+	var locker dbcore.Locker
+	if s.newLocker != nil {
+		locker = s.newLocker("job:" + e.job.Name)
+		ok, err := locker.Lock()
+		if err != nil {
+			return nil, nil, errx.WithStackOnce(err)
+		}
+
+		if !ok {
+			return nil, nil, errors2.Wrapf(errcode.Err_conflict, "job %s is running", e.job.Name)
+		}
+	}
+
+	run, err := s.runs.Create(ctx, &jobmodel.JobRun{
+		Job:         e.job.Name,
+		StartedAt:   time.Now(),
+		ScheduledAt: scheduledAt,
+		Status:      jobmodel.RunRunning,
+		Holder:      s.identity,
+		Manual:      scheduledAt == nil,
+	})
+	if err != nil {
+		if locker != nil {
+			_ = locker.UnLock()
+		}
+		return nil, nil, err
+	}
+
+	return run, locker, nil
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, run *jobmodel.JobRun, locker dbcore.Locker) {
+	defer s.setRunning(e, false)
+
+	if locker != nil {
+		defer func() {
+			_ = locker.UnLock()
+		}()
+
+		// 租约丢失时中止，避免与新的持有者同时执行
+		var cancel context.CancelFunc
+		ctx, cancel = dbcore.LeaseContext(ctx, locker)
+		defer cancel()
+	}
+
+	if e.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+		defer cancel()
+	}
+
+	log.Infof("job %s started, run: %s", e.job.Name, run.Id)
+	err := safeRun(ctx, e.job)
+
+	now := time.Now()
+	run.EndedAt = &now
+	if err != nil {
+		log.Errorf("job %s failed: %+v", e.job.Name, err)
+		run.Status = jobmodel.RunFailed
+		run.Error = err.Error()
+	} else {
+		log.Infof("job %s succeeded, cost: %s", e.job.Name, now.Sub(run.StartedAt))
+		run.Status = jobmodel.RunSucceeded
+	}
+
+	// 退出时 ctx 已取消，仍然需要记录结果
+	if err := s.runs.Finish(context.Background(), run); err != nil {
+		log.Errorf("save job run %s err: %+v", run.Id, err)
+	}
+}
+
+func safeRun(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors2.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(ctx)
+}
+
+// 按名称排序，带上最近一次执行记录
+func (s *Scheduler) Jobs(ctx context.Context) ([]*JobStatus, error) {
+	s.mu.Lock()
+	r := make([]*JobStatus, 0, len(s.entries))
+	names := make([]string, 0, len(s.entries))
+	for _, v := range s.entries {
+		r = append(r, &JobStatus{
+			Name:    v.job.Name,
+			Spec:    v.job.Spec,
+			Next:    v.next,
+			Running: v.running,
+		})
+		names = append(names, v.job.Name)
+	}
+	s.mu.Unlock()
+
+	last, err := s.runs.Last(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range r {
+		v.LastRun = last[v.Name]
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+	return r, nil
+}
+
+func (s *Scheduler) setNext(e *entry, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.next = next
+}
+
+func (s *Scheduler) setRunning(e *entry, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.running = running
+}
@@ -0,0 +1,213 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	jobmodel "github.com/win5do/golang-microservice-demo/pkg/model/job"
+	memjob "github.com/win5do/golang-microservice-demo/pkg/repository/memory/job"
+)
+
+func lastRun(t *testing.T, s *Scheduler, name string) *jobmodel.JobRun {
+	jobs, err := s.Jobs(context.Background())
+	require.NoError(t, err)
+	for _, v := range jobs {
+		if v.Name == name {
+			return v.LastRun
+		}
+	}
+	t.Fatalf("job not found: %s", name)
+	return nil
+}
+
+func waitFinished(t *testing.T, s *Scheduler, name string) *jobmodel.JobRun {
+	for i := 0; i < 100; i++ {
+		if run := lastRun(t, s, name); run != nil && run.Status != jobmodel.RunRunning {
+			return run
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job not finished: %s", name)
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	s := NewScheduler(context.Background(), "a", memjob.NewJobRunDb(), nil)
+	noop := func(ctx context.Context) error { return nil }
+
+	require.NoError(t, s.Register(&Job{Name: "a", Spec: "*/5 * * * *", Run: noop}))
+	require.NoError(t, s.Register(&Job{Name: "b", Spec: "@every 1h", Run: noop}))
+	require.Error(t, s.Register(&Job{Name: "a", Spec: "@hourly", Run: noop}))
+	require.Error(t, s.Register(&Job{Name: "c", Spec: "invalid", Run: noop}))
+
+	// @every 对齐到间隔，与注册时间无关
+	now := time.Now()
+	require.Equal(t, now.Truncate(time.Hour).Add(time.Hour), s.entries["b"].schedule.Next(now))
+}
+
+func TestTrigger(t *testing.T) {
+	ctx, cancel := util.NewWaitGroupCtx()
+	defer cancel()
+
+	s := NewScheduler(ctx, "a", memjob.NewJobRunDb(), nil)
+	release := make(chan struct{})
+	require.NoError(t, s.Register(&Job{
+		Name: "block",
+		Spec: "@hourly",
+		Run: func(ctx context.Context) error {
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, s.Register(&Job{
+		Name: "fail",
+		Spec: "@hourly",
+		Run: func(ctx context.Context) error {
+			return errors.New("boom")
+		},
+	}))
+	require.NoError(t, s.Register(&Job{
+		Name: "panic",
+		Spec: "@hourly",
+		Run: func(ctx context.Context) error {
+			panic("boom")
+		},
+	}))
+
+	_, err := s.Trigger(context.Background(), "unknown")
+	require.True(t, errors2.Is(err, errcode.Err_not_found))
+
+	run, err := s.Trigger(context.Background(), "block")
+	require.NoError(t, err)
+	require.Equal(t, jobmodel.RunRunning, run.Status)
+	require.True(t, run.Manual)
+	require.Equal(t, "a", run.Holder)
+
+	// 正在执行时不能重复触发
+	_, err = s.Trigger(context.Background(), "block")
+	require.True(t, errors2.Is(err, errcode.Err_conflict))
+
+	close(release)
+	run = waitFinished(t, s, "block")
+	require.Equal(t, jobmodel.RunSucceeded, run.Status)
+	require.NotNil(t, run.EndedAt)
+
+	_, err = s.Trigger(context.Background(), "fail")
+	require.NoError(t, err)
+	run = waitFinished(t, s, "fail")
+	require.Equal(t, jobmodel.RunFailed, run.Status)
+	require.Equal(t, "boom", run.Error)
+
+	_, err = s.Trigger(context.Background(), "panic")
+	require.NoError(t, err)
+	run = waitFinished(t, s, "panic")
+	require.Equal(t, jobmodel.RunFailed, run.Status)
+	require.Contains(t, run.Error, "boom")
+
+	cancel()
+	util.GetWaitGroupInCtx(ctx).Wait()
+}
+
+func TestSchedulerRun(t *testing.T) {
+	ctx, cancel := util.NewWaitGroupCtx()
+	defer cancel()
+
+	s := NewScheduler(ctx, "a", memjob.NewJobRunDb(), nil)
+	done := make(chan struct{}, 1)
+	require.NoError(t, s.Register(&Job{
+		Name: "tick",
+		Spec: "@every 1s",
+		Run: func(ctx context.Context) error {
+			select {
+			case done <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}))
+
+	go s.Run(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job should be scheduled")
+	}
+
+	jobs, err := s.Jobs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.False(t, jobs[0].Next.IsZero())
+
+	cancel()
+	util.GetWaitGroupInCtx(ctx).Wait()
+}
+
+// 稍晚创建执行记录，模拟定时器晚几毫秒触发的副本
+type delayedRuns struct {
+	jobmodel.IJobRunDb
+	delay time.Duration
+}
+
+func (s *delayedRuns) Create(ctx context.Context, in *jobmodel.JobRun) (*jobmodel.JobRun, error) {
+	time.Sleep(s.delay)
+	return s.IJobRunDb.Create(ctx, in)
+}
+
+func TestSchedulerSlot(t *testing.T) {
+	ctx, cancel := util.NewWaitGroupCtx()
+	defer cancel()
+
+	runs := memjob.NewJobRunDb()
+	var mu sync.Mutex
+	count := 0
+	newScheduler := func(identity string, runs jobmodel.IJobRunDb) *Scheduler {
+		s := NewScheduler(ctx, identity, runs, nil)
+		require.NoError(t, s.Register(&Job{
+			Name: "tick",
+			Spec: "@hourly",
+			Run: func(ctx context.Context) error {
+				mu.Lock()
+				count++
+				mu.Unlock()
+				return nil
+			},
+		}))
+		s.entries["tick"].schedule = alignedSchedule(100 * time.Millisecond)
+		return s
+	}
+
+	// b 在 a 执行完之后才到达同一个时间点
+	a := newScheduler("a", runs)
+	b := newScheduler("b", &delayedRuns{IJobRunDb: runs, delay: 5 * time.Millisecond})
+	go a.Run(ctx)
+	go b.Run(ctx)
+
+	time.Sleep(550 * time.Millisecond)
+	cancel()
+	util.GetWaitGroupInCtx(ctx).Wait()
+
+	list, err := runs.List(context.Background(), "tick", 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(list), 3)
+
+	slots := map[time.Time]bool{}
+	for _, v := range list {
+		require.NotNil(t, v.ScheduledAt)
+		require.False(t, v.Manual)
+		require.False(t, slots[*v.ScheduledAt], "slot %s run twice", v.ScheduledAt)
+		slots[*v.ScheduledAt] = true
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, len(list), count)
+}
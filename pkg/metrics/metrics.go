@@ -0,0 +1,127 @@
+// Package metrics 汇总 grpc、gateway、数据库和 go runtime 的 prometheus 指标
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+// 未匹配到 rpc 的请求，如 404
+const unmatchedRoute = "unmatched"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// grpc server 的拦截器，开启了处理耗时直方图
+	Grpc *grpc_prometheus.ServerMetrics
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// namespace 作为除 go runtime 和进程指标以外所有指标的前缀，为空不加前缀
+func New(namespace string) (*Metrics, error) {
+	s := &Metrics{
+		registry: prometheus.NewRegistry(),
+		Grpc:     grpc_prometheus.NewServerMetrics(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Gateway http requests by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of gateway http requests by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+	s.Grpc.EnableHandlingTimeHistogram()
+
+	var reg prometheus.Registerer = s.registry
+	if namespace != "" {
+		reg = prometheus.WrapRegistererWithPrefix(namespace+"_", reg)
+	}
+
+	for _, v := range []prometheus.Collector{s.Grpc, s.httpRequests, s.httpDuration} {
+		if err := reg.Register(v); err != nil {
+			return nil, errx.WithStackOnce(err)
+		}
+	}
+
+	if err := dbcore.RegisterMetrics(reg); err != nil {
+		return nil, err
+	}
+
+	err := s.registry.Register(prometheus.NewGoCollector())
+	if err == nil {
+		err = s.registry.Register(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	}
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	return s, nil
+}
+
+// /metrics 的处理函数
+func (s *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{})
+}
+
+type ctxRouteKey struct{}
+
+type route struct {
+	name string
+}
+
+// gateway 的 ServeMuxOption，把匹配到的 rpc 方法记录到请求的 route 中
+//
+// gateway 不暴露匹配的路径模板，使用 rpc 全名作为路由标签，如 /pet.service.v1.PetService/GetPet，避免路径参数导致标签过多
+func (s *Metrics) GatewayOption() runtime.ServeMuxOption {
+	return runtime.WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+		rt, ok := r.Context().Value(ctxRouteKey{}).(*route)
+		if !ok {
+			return nil
+		}
+
+		if method, ok := runtime.RPCMethod(ctx); ok {
+			rt.name = method
+		}
+		return nil
+	})
+}
+
+// 记录 gateway 每个路由的请求数和耗时，需要同时使用 GatewayOption
+func (s *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rt := &route{name: unmatchedRoute}
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), ctxRouteKey{}, rt)))
+
+		s.httpRequests.WithLabelValues(r.Method, rt.name, strconv.Itoa(sw.code)).Inc()
+		s.httpDuration.WithLabelValues(r.Method, rt.name).Observe(time.Since(start).Seconds())
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	s.code = code
+	s.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/petpb"
+)
+
+type petServer struct {
+	petpb.UnimplementedPetServiceServer
+}
+
+func TestGatewayMetrics(t *testing.T) {
+	m, err := New("demo")
+	require.NoError(t, err)
+
+	mux := runtime.NewServeMux(m.GatewayOption())
+	require.NoError(t, petpb.RegisterPetServiceGWServer(context.Background(), mux, &petServer{}))
+	handler := m.Middleware(mux)
+
+	for _, path := range []string{"/v1/pets/1", "/v1/pets/2", "/not-found"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// 不同的路径参数归到同一个路由
+	require.Equal(t, float64(2), testutil.ToFloat64(
+		m.httpRequests.WithLabelValues(http.MethodGet, "/pet.service.v1.PetService/GetPet", "501")))
+	require.Equal(t, float64(1), testutil.ToFloat64(
+		m.httpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	// 加上命名空间前缀，runtime 指标不加
+	for _, name := range []string{
+		"demo_http_requests_total",
+		"demo_http_request_duration_seconds_bucket",
+		"go_goroutines",
+	} {
+		require.True(t, strings.Contains(string(body), name), name)
+	}
+}
@@ -2,13 +2,23 @@ package model
 
 import (
 	"context"
+	"database/sql"
+	"strconv"
+	"strings"
 	"time"
+
+	errors2 "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
 )
 
 type Common struct {
 	Id        string `gorm:"primarykey"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64          `gorm:"not null;default:1"` // 乐观锁版本号，每次更新加 1
+	DeletedAt gorm.DeletedAt `gorm:"index"`              // 软删除，查询时自动过滤
 }
 
 type Object interface {
@@ -19,12 +29,79 @@ func (s *Common) GetId() string {
 	return s.Id
 }
 
+// etag 由版本号生成，客户端原样传回即可
+func (s *Common) Etag() string {
+	if s.Version == 0 {
+		return ""
+	}
+	return strconv.FormatInt(s.Version, 10)
+}
+
+// 解析 etag 为版本号，兼容 If-Match 中的 W/"1" 和 "1" 格式
+func ParseEtag(etag string) (int64, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	etag = strings.Trim(etag, `"`)
+
+	version, err := strconv.ParseInt(etag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errors2.Wrapf(errcode.Err_invalid_params, "etag: %s", etag)
+	}
+
+	return version, nil
+}
+
+// 嵌套调用时复用外层事务，内层失败只回滚到 savepoint，外层可以选择继续
 type ITransaction interface {
-	Transaction(ctx context.Context, fn func(txctx context.Context) error) error
+	Transaction(ctx context.Context, fn func(txctx context.Context) error, opts ...TxOption) error
+}
+
+type TxOptions struct {
+	Isolation sql.IsolationLevel // 默认使用数据库的隔离级别
+	ReadOnly  bool
+	Timeout   time.Duration // 整个事务的超时，包括重试，0 表示不限制
+}
+
+// 隔离级别和只读只对最外层事务生效，嵌套调用时忽略
+type TxOption func(*TxOptions)
+
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+func WithReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+func WithTxTimeout(timeout time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.Timeout = timeout
+	}
+}
+
+func NewTxOptions(opts ...TxOption) *TxOptions {
+	o := &TxOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// 超时时返回带 deadline 的 ctx
+func (s *TxOptions) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Timeout > 0 {
+		return context.WithTimeout(ctx, s.Timeout)
+	}
+	return ctx, func() {}
 }
 
 type NoopTransaction struct{}
 
-func (*NoopTransaction) Transaction(ctx context.Context, fn func(txctx context.Context) error) error {
+func (*NoopTransaction) Transaction(ctx context.Context, fn func(txctx context.Context) error, opts ...TxOption) error {
+	ctx, cancel := NewTxOptions(opts...).Context(ctx)
+	defer cancel()
 	return fn(ctx)
 }
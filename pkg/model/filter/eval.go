@@ -0,0 +1,94 @@
+package filter
+
+import (
+	"strings"
+	"time"
+)
+
+// 在内存中对 struct 求值，用于不支持 sql 的存储实现
+func Match(expr Expr, obj interface{}) bool {
+	switch e := expr.(type) {
+	case nil:
+		return true
+	case *Compare:
+		c, ok := CompareValues(ValueOf(obj, e.Field), e.Value)
+		if !ok {
+			return false
+		}
+
+		switch e.Op {
+		case Eq:
+			return c == 0
+		case Neq:
+			return c != 0
+		case Lt:
+			return c < 0
+		case Lte:
+			return c <= 0
+		case Gt:
+			return c > 0
+		case Gte:
+			return c >= 0
+		}
+	case *And:
+		return Match(e.Left, obj) && Match(e.Right, obj)
+	case *Or:
+		return Match(e.Left, obj) || Match(e.Right, obj)
+	case *Not:
+		return !Match(e.Expr, obj)
+	}
+
+	return false
+}
+
+// 比较两个同类型的值，类型不一致时返回 false
+func CompareValues(a, b interface{}) (int, bool) {
+	a, b = normalize(a), normalize(b)
+
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case int64:
+		y, ok := b.(int64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case bool:
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x == y:
+			return 0, true
+		case !x:
+			return -1, true
+		}
+		return 1, true
+	case time.Time:
+		y, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x.Before(y):
+			return -1, true
+		case x.After(y):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
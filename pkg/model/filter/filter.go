@@ -0,0 +1,120 @@
+// Package filter 解析 AIP-160 风格的过滤表达式和 order_by，
+// 如：type = "cat" AND age > 2 AND owned = false
+//
+// 字段必须在 Fields 白名单中，值按字段类型转换，由 repository 层翻译为具体的查询语句
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	Time
+)
+
+func (k Kind) String() string {
+	switch k {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time"
+	}
+	return "unknown"
+}
+
+// 允许过滤和排序的字段，key 为 api 字段名，与表的列名一致
+type Fields map[string]Kind
+
+type Op string
+
+const (
+	Eq  Op = "="
+	Neq Op = "!="
+	Lt  Op = "<"
+	Lte Op = "<="
+	Gt  Op = ">"
+	Gte Op = ">="
+)
+
+type Expr interface {
+	isExpr()
+}
+
+// field op value
+type Compare struct {
+	Field string
+	Op    Op
+	Value interface{} // string, int64, bool, time.Time
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+func (*Compare) isExpr() {}
+func (*And) isExpr()     {}
+func (*Or) isExpr()      {}
+func (*Not) isExpr()     {}
+
+// 按字段名取 struct 的值，created_at 对应 CreatedAt，支持嵌入字段
+func ValueOf(obj interface{}, field string) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	f := v.FieldByName(camelCase(field))
+	if !f.IsValid() {
+		return nil
+	}
+
+	return normalize(f.Interface())
+}
+
+// 统一数值类型，便于比较和序列化
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case uint:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint64:
+		return int64(x)
+	case time.Time:
+		return x
+	}
+	return v
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testFields = Fields{
+	"name":       String,
+	"type":       String,
+	"age":        Int,
+	"owned":      Bool,
+	"created_at": Time,
+}
+
+func TestParse(t *testing.T) {
+	expr, err := Parse(`type = "cat" AND age > 2 AND owned = false`, testFields)
+	require.NoError(t, err)
+	require.Equal(t, &And{
+		Left: &And{
+			Left:  &Compare{Field: "type", Op: Eq, Value: "cat"},
+			Right: &Compare{Field: "age", Op: Gt, Value: int64(2)},
+		},
+		Right: &Compare{Field: "owned", Op: Eq, Value: false},
+	}, expr)
+
+	// OR 优先级高于 AND
+	expr, err = Parse(`NOT name = 'gugu' AND age <= 1 OR age >= 10`, testFields)
+	require.NoError(t, err)
+	require.Equal(t, &And{
+		Left: &Not{Expr: &Compare{Field: "name", Op: Eq, Value: "gugu"}},
+		Right: &Or{
+			Left:  &Compare{Field: "age", Op: Lte, Value: int64(1)},
+			Right: &Compare{Field: "age", Op: Gte, Value: int64(10)},
+		},
+	}, expr)
+
+	expr, err = Parse(`-(created_at < "2021-01-02T15:04:05Z")`, testFields)
+	require.NoError(t, err)
+	require.Equal(t, &Not{Expr: &Compare{
+		Field: "created_at",
+		Op:    Lt,
+		Value: time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+	}}, expr)
+
+	expr, err = Parse("  ", testFields)
+	require.NoError(t, err)
+	require.Nil(t, expr)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, v := range []string{
+		`color = "red"`,
+		`age > "two"`,
+		`owned > true`,
+		`name = "gugu`,
+		`(age > 1`,
+		`age > 1 age < 2`,
+		`name = "a"; drop table tb_pets`,
+		`created_at > "yesterday"`,
+	} {
+		_, err := Parse(v, testFields)
+		require.Error(t, err, v)
+	}
+}
+
+func TestParseOrderBy(t *testing.T) {
+	r, err := ParseOrderBy("created_at desc, age", testFields)
+	require.NoError(t, err)
+	require.Equal(t, []Order{{Field: "created_at", Desc: true}, {Field: "age"}}, r)
+	require.Equal(t, "created_at desc,age", OrderString(r))
+
+	for _, v := range []string{"color", "age up", "age, age desc", "age desc nulls"} {
+		_, err := ParseOrderBy(v, testFields)
+		require.Error(t, err, v)
+	}
+}
+
+func TestValueOf(t *testing.T) {
+	type common struct {
+		CreatedAt time.Time
+	}
+	obj := &struct {
+		common
+		Age uint32
+	}{
+		common: common{CreatedAt: time.Unix(0, 0)},
+		Age:    3,
+	}
+
+	require.Equal(t, int64(3), ValueOf(obj, "age"))
+	require.Equal(t, time.Unix(0, 0), ValueOf(obj, "created_at"))
+	require.Nil(t, ValueOf(obj, "name"))
+}
+
+func TestMatch(t *testing.T) {
+	fields := Fields{"name": String, "age": Int}
+	obj := &struct {
+		Name string
+		Age  uint32
+	}{Name: "gugu", Age: 3}
+
+	for input, want := range map[string]bool{
+		"":                             true,
+		`name = "gugu"`:                true,
+		`name != "gugu"`:               false,
+		"age >= 3 AND age < 4":         true,
+		`age > 3 OR name = "gugu"`:     true,
+		`NOT name = "gugu"`:            false,
+		`name = "qq" OR name = "gugu"`: true,
+	} {
+		expr, err := Parse(input, fields)
+		require.NoError(t, err)
+		require.Equal(t, want, Match(expr, obj), input)
+	}
+}
@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"strings"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+)
+
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// 解析 order_by，如："created_at desc, age"
+func ParseOrderBy(input string, fields Fields) ([]Order, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	var r []Order
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(input, ",") {
+		parts := strings.Fields(item)
+
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, errors2.Wrapf(errcode.Err_invalid_params, "order_by: %q", item)
+		}
+
+		if _, ok := fields[parts[0]]; !ok {
+			return nil, errors2.Wrapf(errcode.Err_invalid_params, "order_by: unknown field %q", parts[0])
+		}
+
+		if seen[parts[0]] {
+			return nil, errors2.Wrapf(errcode.Err_invalid_params, "order_by: duplicate field %q", parts[0])
+		}
+		seen[parts[0]] = true
+
+		o := Order{Field: parts[0]}
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				o.Desc = true
+			default:
+				return nil, errors2.Wrapf(errcode.Err_invalid_params, "order_by: %q", item)
+			}
+		}
+
+		r = append(r, o)
+	}
+
+	return r, nil
+}
+
+// 规范化后的 order_by，用于校验翻页前后排序是否一致
+func OrderString(orders []Order) string {
+	var parts []string
+	for _, v := range orders {
+		if v.Desc {
+			parts = append(parts, v.Field+" desc")
+		} else {
+			parts = append(parts, v.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,308 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+)
+
+const (
+	maxLength = 1024 // 表达式最大长度
+	maxDepth  = 32   // 最大嵌套层数
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokMinus
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func lex(input string) ([]token, error) {
+	var r []token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			r = append(r, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			r = append(r, token{tokRParen, ")", i})
+			i++
+		case c == '=':
+			r = append(r, token{tokOp, "=", i})
+			i++
+		case c == '!' || c == '<' || c == '>':
+			if i+1 < len(input) && input[i+1] == '=' {
+				r = append(r, token{tokOp, input[i : i+2], i})
+				i += 2
+				continue
+			}
+			if c == '!' {
+				return nil, syntaxErr(i, "unexpected '!'")
+			}
+			r = append(r, token{tokOp, string(c), i})
+			i++
+		case c == '"' || c == '\'':
+			s, n, err := lexString(input[i:])
+			if err != nil {
+				return nil, syntaxErr(i, err.Error())
+			}
+			r = append(r, token{tokString, s, i})
+			i += n
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(input) && (input[j] >= '0' && input[j] <= '9' || input[j] == '.') {
+				j++
+			}
+			if c == '-' && j == i+1 {
+				// 前缀 - 等同于 NOT
+				r = append(r, token{tokMinus, "-", i})
+				i++
+				continue
+			}
+			r = append(r, token{tokNumber, input[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(input) && (input[j] == '_' || input[j] == '.' ||
+				unicode.IsLetter(rune(input[j])) || unicode.IsDigit(rune(input[j]))) {
+				j++
+			}
+			r = append(r, token{tokIdent, input[i:j], i})
+			i = j
+		default:
+			return nil, syntaxErr(i, "unexpected character %q", c)
+		}
+	}
+
+	return append(r, token{tokEOF, "", len(input)}), nil
+}
+
+// 返回去掉引号和转义后的字符串，以及消耗的字节数
+func lexString(input string) (string, int, error) {
+	quote := input[0]
+	var b strings.Builder
+	for i := 1; i < len(input); i++ {
+		c := input[i]
+		switch c {
+		case '\\':
+			if i+1 >= len(input) {
+				return "", 0, errors2.New("unterminated string")
+			}
+			i++
+			b.WriteByte(input[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, errors2.New("unterminated string")
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	fields Fields
+}
+
+// 解析过滤表达式，空字符串返回 nil
+//
+// 按 AIP-160，OR 的优先级高于 AND：a AND b OR c 等价于 a AND (b OR c)
+func Parse(input string, fields Fields) (Expr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	if len(input) > maxLength {
+		return nil, errors2.Wrapf(errcode.Err_invalid_params, "filter too long: %d", len(input))
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		tokens: tokens,
+		fields: fields,
+	}
+
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, syntaxErr(t.pos, "unexpected %q", t.val)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.val == kw
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, syntaxErr(p.peek().pos, "too deeply nested")
+	}
+
+	if p.isKeyword("NOT") || p.peek().kind == tokMinus {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, syntaxErr(t.pos, "expect ')'")
+		}
+		return expr, nil
+	}
+
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (Expr, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, syntaxErr(t.pos, "expect field, got %q", t.val)
+	}
+
+	kind, ok := p.fields[t.val]
+	if !ok {
+		return nil, errors2.Wrapf(errcode.Err_invalid_params, "filter: unknown field %q", t.val)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, syntaxErr(opTok.pos, "expect operator after %q", t.val)
+	}
+	op := Op(opTok.val)
+
+	if kind == Bool && op != Eq && op != Neq {
+		return nil, errors2.Wrapf(errcode.Err_invalid_params, "filter: operator %s not supported on %q", op, t.val)
+	}
+
+	valTok := p.next()
+	val, err := convert(kind, valTok)
+	if err != nil {
+		return nil, errors2.Wrapf(errcode.Err_invalid_params, "filter: field %q: %s", t.val, err)
+	}
+
+	return &Compare{
+		Field: t.val,
+		Op:    op,
+		Value: val,
+	}, nil
+}
+
+func convert(kind Kind, t token) (interface{}, error) {
+	switch kind {
+	case String:
+		if t.kind == tokString {
+			return t.val, nil
+		}
+	case Int:
+		if t.kind == tokNumber {
+			return strconv.ParseInt(t.val, 10, 64)
+		}
+	case Bool:
+		if t.kind == tokIdent && (t.val == "true" || t.val == "false") {
+			return t.val == "true", nil
+		}
+	case Time:
+		if t.kind == tokString {
+			return time.Parse(time.RFC3339, t.val)
+		}
+	}
+
+	return nil, errors2.Errorf("expect %s value, got %q", kind, t.val)
+}
+
+func syntaxErr(pos int, format string, args ...interface{}) error {
+	return errors2.Wrapf(errcode.Err_invalid_params, "filter: syntax error at %d: %s", pos, errors2.Errorf(format, args...))
+}
@@ -0,0 +1,39 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// 定时任务的一次执行记录
+type JobRun struct {
+	Id        string    `gorm:"primarykey"`
+	Job       string    `gorm:"index:idx_job_run_job_started;uniqueIndex:idx_job_run_job_scheduled;size:191;not null"`
+	StartedAt time.Time `gorm:"index:idx_job_run_job_started"`
+	// 调度的时间点，同一个任务的同一个时间点只执行一次，手动触发时为空
+	ScheduledAt *time.Time `gorm:"uniqueIndex:idx_job_run_job_scheduled"`
+	EndedAt     *time.Time
+	Status      RunStatus `gorm:"size:16;not null"`
+	Error       string    `gorm:"type:text"`
+	Holder      string    // 执行的副本
+	Manual      bool      // 手动触发
+}
+
+type IJobRunDb interface {
+	// 同一个任务的 ScheduledAt 已存在时返回 errcode.Err_conflict
+	Create(ctx context.Context, in *JobRun) (*JobRun, error)
+	// 更新结束时间、状态和错误信息
+	Finish(ctx context.Context, in *JobRun) error
+	// 每个任务最近一次执行，没有执行过的任务不在结果中
+	Last(ctx context.Context, jobs ...string) (map[string]*JobRun, error)
+	// 按开始时间倒序
+	List(ctx context.Context, job string, limit int) ([]*JobRun, error)
+}
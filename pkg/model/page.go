@@ -0,0 +1,147 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 1000
+)
+
+// 列表查询参数：过滤、排序和 keyset 分页
+type Page struct {
+	Size        int
+	Filter      filter.Expr
+	OrderBy     []filter.Order
+	Cursor      *Cursor // nil 表示第一页
+	ShowDeleted bool    // 包含已软删除的记录
+}
+
+// 上一页最后一条记录的位置，Id 为 ulid 按时间有序，作为排序的最后一列保证顺序稳定
+type Cursor struct {
+	Id     string        `json:"id"`
+	Order  string        `json:"o,omitempty"` // 生成 token 时的 order_by
+	Values []interface{} `json:"v,omitempty"` // 与 OrderBy 一一对应
+}
+
+func PageSize(size int) (int, error) {
+	switch {
+	case size < 0:
+		return 0, errors2.Wrapf(errcode.Err_invalid_params, "page size: %d", size)
+	case size == 0:
+		return DefaultPageSize, nil
+	case size > MaxPageSize:
+		return MaxPageSize, nil
+	}
+
+	return size, nil
+}
+
+// 查询时多取一条，取到时说明还有下一页，避免总数正好是整页时多返回一个空页
+func (s *Page) Limit() int {
+	return s.Size + 1
+}
+
+// 返回下一页的 token，last 为本页最后一条，没有下一页时为 nil
+func (s *Page) NextToken(last Object) string {
+	if last == nil {
+		return ""
+	}
+
+	c := &Cursor{
+		Id:    last.GetId(),
+		Order: filter.OrderString(s.OrderBy),
+	}
+	for _, v := range s.OrderBy {
+		c.Values = append(c.Values, filter.ValueOf(last, v.Field))
+	}
+
+	return EncodePageToken(c)
+}
+
+func EncodePageToken(c *Cursor) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// 解析 token，token 对客户端不透明，但仍需校验，避免拼接任意值
+func DecodePageToken(token string, orderBy []filter.Order, fields filter.Fields) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	invalid := errors2.Wrapf(errcode.Err_invalid_params, "page token: %s", token)
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var c Cursor
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, invalid
+	}
+
+	if _, err := ulid.ParseStrict(c.Id); err != nil {
+		return nil, invalid
+	}
+
+	// 翻页时不允许修改排序
+	if c.Order != filter.OrderString(orderBy) || len(c.Values) != len(orderBy) {
+		return nil, invalid
+	}
+
+	for i, v := range orderBy {
+		val, ok := cursorValue(fields[v.Field], c.Values[i])
+		if !ok {
+			return nil, invalid
+		}
+		c.Values[i] = val
+	}
+
+	return &c, nil
+}
+
+// json 解码后的值按字段类型还原
+func cursorValue(kind filter.Kind, v interface{}) (interface{}, bool) {
+	switch kind {
+	case filter.String:
+		s, ok := v.(string)
+		return s, ok
+	case filter.Int:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, false
+		}
+		i, err := n.Int64()
+		return i, err == nil
+	case filter.Bool:
+		b, ok := v.(bool)
+		return b, ok
+	case filter.Time:
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t, err == nil
+	}
+
+	return nil, false
+}
@@ -7,8 +7,10 @@ package mock_pet
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	model "github.com/win5do/golang-microservice-demo/pkg/model"
 	pet "github.com/win5do/golang-microservice-demo/pkg/model/pet"
 )
 
@@ -100,6 +102,21 @@ func (m *MockIPetDb) EXPECT() *MockIPetDbMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockIPetDb) Count(arg0 *pet.Pet, arg1 *model.Page) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockIPetDbMockRecorder) Count(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockIPetDb)(nil).Count), arg0, arg1)
+}
+
 // Create mocks base method.
 func (m *MockIPetDb) Create(arg0 *pet.Pet) (*pet.Pet, error) {
 	m.ctrl.T.Helper()
@@ -159,19 +176,69 @@ func (mr *MockIPetDbMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIPetDb)(nil).List), arg0, arg1, arg2)
 }
 
+// Page mocks base method.
+func (m *MockIPetDb) Page(arg0 *pet.Pet, arg1 *model.Page) ([]*pet.Pet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Page", arg0, arg1)
+	ret0, _ := ret[0].([]*pet.Pet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Page indicates an expected call of Page.
+func (mr *MockIPetDbMockRecorder) Page(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Page", reflect.TypeOf((*MockIPetDb)(nil).Page), arg0, arg1)
+}
+
+// Purge mocks base method.
+func (m *MockIPetDb) Purge(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockIPetDbMockRecorder) Purge(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockIPetDb)(nil).Purge), arg0)
+}
+
+// Undelete mocks base method.
+func (m *MockIPetDb) Undelete(arg0 *pet.Pet) (*pet.Pet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", arg0)
+	ret0, _ := ret[0].(*pet.Pet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockIPetDbMockRecorder) Undelete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockIPetDb)(nil).Undelete), arg0)
+}
+
 // Update mocks base method.
-func (m *MockIPetDb) Update(arg0 *pet.Pet) (*pet.Pet, error) {
+func (m *MockIPetDb) Update(arg0 *pet.Pet, arg1 ...string) (*pet.Pet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(*pet.Pet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockIPetDbMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockIPetDbMockRecorder) Update(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIPetDb)(nil).Update), arg0)
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIPetDb)(nil).Update), varargs...)
 }
 
 // MockIOwnerDb is a mock of IOwnerDb interface.
@@ -197,6 +264,21 @@ func (m *MockIOwnerDb) EXPECT() *MockIOwnerDbMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockIOwnerDb) Count(arg0 *pet.Owner, arg1 *model.Page) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockIOwnerDbMockRecorder) Count(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockIOwnerDb)(nil).Count), arg0, arg1)
+}
+
 // Create mocks base method.
 func (m *MockIOwnerDb) Create(arg0 *pet.Owner) (*pet.Owner, error) {
 	m.ctrl.T.Helper()
@@ -256,19 +338,69 @@ func (mr *MockIOwnerDbMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIOwnerDb)(nil).List), arg0, arg1, arg2)
 }
 
+// Page mocks base method.
+func (m *MockIOwnerDb) Page(arg0 *pet.Owner, arg1 *model.Page) ([]*pet.Owner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Page", arg0, arg1)
+	ret0, _ := ret[0].([]*pet.Owner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Page indicates an expected call of Page.
+func (mr *MockIOwnerDbMockRecorder) Page(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Page", reflect.TypeOf((*MockIOwnerDb)(nil).Page), arg0, arg1)
+}
+
+// Purge mocks base method.
+func (m *MockIOwnerDb) Purge(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockIOwnerDbMockRecorder) Purge(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockIOwnerDb)(nil).Purge), arg0)
+}
+
+// Undelete mocks base method.
+func (m *MockIOwnerDb) Undelete(arg0 *pet.Owner) (*pet.Owner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", arg0)
+	ret0, _ := ret[0].(*pet.Owner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockIOwnerDbMockRecorder) Undelete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockIOwnerDb)(nil).Undelete), arg0)
+}
+
 // Update mocks base method.
-func (m *MockIOwnerDb) Update(arg0 *pet.Owner) (*pet.Owner, error) {
+func (m *MockIOwnerDb) Update(arg0 *pet.Owner, arg1 ...string) (*pet.Owner, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(*pet.Owner)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockIOwnerDbMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockIOwnerDbMockRecorder) Update(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIOwnerDb)(nil).Update), arg0)
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIOwnerDb)(nil).Update), varargs...)
 }
 
 // MockIOwnerPetDb is a mock of IOwnerPetDb interface.
@@ -323,6 +455,21 @@ func (mr *MockIOwnerPetDbMockRecorder) Delete(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIOwnerPetDb)(nil).Delete), arg0)
 }
 
+// Purge mocks base method.
+func (m *MockIOwnerPetDb) Purge(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockIOwnerPetDbMockRecorder) Purge(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockIOwnerPetDb)(nil).Purge), arg0)
+}
+
 // Query mocks base method.
 func (m *MockIOwnerPetDb) Query(arg0 *pet.OwnerPet) ([]*pet.OwnerPet, error) {
 	m.ctrl.T.Helper()
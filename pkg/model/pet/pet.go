@@ -2,8 +2,10 @@ package pet
 
 import (
 	"context"
+	"time"
 
 	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
 )
 
 type IPetDomain interface {
@@ -21,12 +23,29 @@ type Pet struct {
 	Owned bool
 }
 
+// 可用于 filter 和 order_by 的字段
+var PetFields = filter.Fields{
+	"id":         filter.String,
+	"created_at": filter.Time,
+	"updated_at": filter.Time,
+	"name":       filter.String,
+	"type":       filter.String,
+	"age":        filter.Int,
+	"sex":        filter.String,
+	"owned":      filter.Bool,
+}
+
 type IPetDb interface {
 	Get(id string) (*Pet, error)
 	List(query *Pet, offset, limit int) ([]*Pet, error)
+	// 最多返回 page.Limit() 条，多取的一条用于判断是否还有下一页
+	Page(query *Pet, page *model.Page) ([]*Pet, error)
+	Count(query *Pet, page *model.Page) (int64, error)
 	Create(query *Pet) (*Pet, error)
-	Update(query *Pet) (*Pet, error)
+	Update(query *Pet, fields ...string) (*Pet, error)
 	Delete(query *Pet) error
+	Undelete(query *Pet) (*Pet, error)
+	Purge(before time.Time) (int64, error)
 }
 
 type Owner struct {
@@ -37,12 +56,28 @@ type Owner struct {
 	Phone string
 }
 
+// 可用于 filter 和 order_by 的字段
+var OwnerFields = filter.Fields{
+	"id":         filter.String,
+	"created_at": filter.Time,
+	"updated_at": filter.Time,
+	"name":       filter.String,
+	"age":        filter.Int,
+	"sex":        filter.String,
+	"phone":      filter.String,
+}
+
 type IOwnerDb interface {
 	Get(id string) (*Owner, error)
 	List(query *Owner, offset, limit int) ([]*Owner, error)
+	// 最多返回 page.Limit() 条，多取的一条用于判断是否还有下一页
+	Page(query *Owner, page *model.Page) ([]*Owner, error)
+	Count(query *Owner, page *model.Page) (int64, error)
 	Create(query *Owner) (*Owner, error)
-	Update(query *Owner) (*Owner, error)
+	Update(query *Owner, fields ...string) (*Owner, error)
 	Delete(query *Owner) error
+	Undelete(query *Owner) (*Owner, error)
+	Purge(before time.Time) (int64, error)
 }
 
 type OwnerPet struct {
@@ -55,4 +90,5 @@ type IOwnerPetDb interface {
 	Query(query *OwnerPet) ([]*OwnerPet, error)
 	Create(query *OwnerPet) (*OwnerPet, error)
 	Delete(query *OwnerPet) error
+	Purge(before time.Time) (int64, error)
 }
@@ -0,0 +1,48 @@
+package dbcore
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	log "github.com/win5do/go-lib/logx"
+)
+
+// 兼容之前的包级函数，操作 SetDefault 设置的数据库
+// 新代码应当显式传递 *DB
+
+var defaultDB *DB
+
+func SetDefault(db *DB) {
+	defaultDB = db
+}
+
+func Default() *DB {
+	if defaultDB == nil {
+		log.Panic("default db not set, call dbcore.SetDefault after Connect")
+	}
+	return defaultDB
+}
+
+// Deprecated: 使用 DB.Get
+func GetDB(ctx context.Context) *gorm.DB {
+	return Default().Get(ctx)
+}
+
+// Deprecated: 使用 DB.CtxWithTransaction
+func CtxWithTransaction(ctx context.Context, tx *gorm.DB) context.Context {
+	return Default().CtxWithTransaction(ctx, tx)
+}
+
+// Deprecated: 使用 DB.Config
+func GetDBConfig() DBConfig {
+	return Default().Config()
+}
+
+// Deprecated: 使用 DB.Close
+func Close() error {
+	if defaultDB == nil {
+		return nil
+	}
+	return defaultDB.Close()
+}
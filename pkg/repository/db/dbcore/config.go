@@ -1,18 +1,52 @@
 package dbcore
 
+import "time"
+
+const (
+	DriverMysql    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSqlite   = "sqlite"
+)
+
 type DBConfig struct {
-	DSN string // data source name
+	Name   string // 用于区分指标和日志，默认 default
+	Driver string // mysql, postgres, sqlite，默认 mysql
+	DSN    string // data source name
+	// 从库，驱动与主库相同，为空时读写都走主库
+	ReplicaDSNs []string
 
 	MaxIdleConns int
 	MaxOpenConns int
-	AutoMigrate  bool // 自动建表，补全缺失字段，初始化数据
+	AutoMigrate  bool // 启动时执行数据库迁移
 	Debug        bool
+
+	// 连接失败时的重试总时长，0 表示不重试，用于数据库晚于服务启动的场景
+	ConnectTimeout time.Duration
+	// 重试间隔，每次翻倍直到 RetryMaxInterval
+	RetryInterval    time.Duration
+	RetryMaxInterval time.Duration
+
+	// 事务遇到死锁或序列化失败时的重试次数，0 表示不重试
+	TxMaxRetries int
+
+	// 超过阈值的查询打印日志，0 表示不打印
+	SlowThreshold time.Duration
+	// 导出连接池指标的间隔，默认 15s
+	MetricsInterval time.Duration
 }
 
 // 默认设置
 func defaultDbConfig(cfg *DBConfig) *DBConfig {
 	newCfg := *cfg
 
+	if newCfg.Name == "" {
+		newCfg.Name = "default"
+	}
+
+	if newCfg.Driver == "" {
+		newCfg.Driver = DriverMysql
+	}
+
 	if newCfg.MaxIdleConns == 0 {
 		newCfg.MaxIdleConns = 10
 	}
@@ -21,5 +55,21 @@ func defaultDbConfig(cfg *DBConfig) *DBConfig {
 		newCfg.MaxOpenConns = 20
 	}
 
+	if newCfg.RetryInterval == 0 {
+		newCfg.RetryInterval = time.Second
+	}
+
+	if newCfg.RetryMaxInterval == 0 {
+		newCfg.RetryMaxInterval = 30 * time.Second
+	}
+
+	if newCfg.MetricsInterval == 0 {
+		newCfg.MetricsInterval = 15 * time.Second
+	}
+
+	if newCfg.RetryMaxInterval < newCfg.RetryInterval {
+		newCfg.RetryMaxInterval = newCfg.RetryInterval
+	}
+
 	return &newCfg
 }
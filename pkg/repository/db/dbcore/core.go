@@ -2,16 +2,15 @@ package dbcore
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
 	"math/rand"
 	"reflect"
-	"strings"
 	"time"
 
 	"gorm.io/gorm/schema"
 
 	"github.com/oklog/ulid/v2"
-	"gorm.io/driver/mysql"
+	errors2 "github.com/pkg/errors"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
@@ -20,26 +19,90 @@ import (
 	"github.com/win5do/go-lib/errx"
 )
 
-var (
-	globalDB *gorm.DB
+// 一个数据库，包括主库、从库连接池和注入函数，同一进程中可以连接多个数据库
+type DB struct {
+	db        *gorm.DB
+	replicas  []*sql.DB
+	config    DBConfig
+	injectors []func(db *gorm.DB) error
+	savepoint uint64 // savepoint 序号
+	stop      chan struct{}
+}
 
-	globalConfig *DBConfig
+type Option func(*DB)
 
-	injectors []func(db *gorm.DB)
-)
+// 连接成功后执行的注入函数，只对当前数据库生效，按添加顺序执行
+func WithInjector(f func(*gorm.DB) error) Option {
+	return func(s *DB) {
+		s.injectors = append(s.injectors, f)
+	}
+}
 
-func Connect(cfg *DBConfig) {
+// 连接数据库，失败时按指数退避重试，直到成功、超过 cfg.ConnectTimeout 或 ctx 取消
+func Connect(ctx context.Context, cfg *DBConfig, opts ...Option) (*DB, error) {
 	cfg = defaultDbConfig(cfg)
-	globalConfig = cfg
+
+	s := &DB{
+		config: *cfg,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// 驱动不支持时不需要重试
+	if _, err := dialector(cfg.Driver, cfg.DSN); err != nil {
+		return nil, err
+	}
+	log.Debugf("db driver: %s, dsn: %s", cfg.Driver, cfg.DSN)
+
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+
+	wait := cfg.RetryInterval
+	for {
+		err := s.open()
+		if err == nil {
+			log.Infof("db connected success, replicas: %d", len(s.replicas))
+			s.exportStats()
+			return s, nil
+		}
+
+		if cfg.ConnectTimeout <= 0 {
+			return nil, err
+		}
+
+		log.Errorf("connect db err: %s, retry in %s", err, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors2.Wrapf(err, "connect db: %s", ctx.Err())
+		}
+
+		wait *= 2
+		if wait > cfg.RetryMaxInterval {
+			wait = cfg.RetryMaxInterval
+		}
+	}
+}
+
+func (s *DB) open() error {
+	cfg := &s.config
 
 	// 连接数据库前初始化Database
-	CreateDatabase(cfg)
+	err := CreateDatabase(cfg)
+	if err != nil {
+		return err
+	}
 
-	dsn := fmt.Sprintf(
-		"%s?charset=utf8&parseTime=True&loc=Local",
-		cfg.DSN,
-	)
-	log.Debugf("db dsn: %s", dsn)
+	dial, err := dialector(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return err
+	}
 
 	var ormLogger logger.Interface
 	if cfg.Debug {
@@ -48,115 +111,152 @@ func Connect(cfg *DBConfig) {
 		ormLogger = logger.Default
 	}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dial, &gorm.Config{
 		Logger: ormLogger,
 		NamingStrategy: schema.NamingStrategy{
 			TablePrefix: "tb_", // 表名前缀，`User` 对应的表名是 `tb_users`
 		},
 	})
 	if err != nil {
-		log.Fatal(err)
+		return errx.WithStackOnce(err)
 	}
 
 	idb, err := db.DB()
 	if err != nil {
-		log.Fatal(err)
+		return errx.WithStackOnce(err)
 	}
 	idb.SetMaxIdleConns(cfg.MaxIdleConns)
 	idb.SetMaxOpenConns(cfg.MaxOpenConns)
 
-	registerCallback(db)
-	callInjector(db)
-	globalDB = db
+	err = idb.Ping()
+	if err == nil {
+		err = registerCallback(db)
+	}
+	if err == nil {
+		err = db.Use(&metricsPlugin{name: cfg.Name, slowThreshold: cfg.SlowThreshold})
+	}
+	if err == nil {
+		err = s.callInjector(db)
+	}
+	if err != nil {
+		_ = idb.Close()
+		return errx.WithStackOnce(err)
+	}
+
+	replicas, err := openReplicas(cfg)
+	if err == nil {
+		err = registerResolver(db, replicas)
+	}
+	if err != nil {
+		closeReplicas(replicas)
+		_ = idb.Close()
+		return errx.WithStackOnce(err)
+	}
 
-	log.Info("db connected success")
+	s.db = db
+	s.replicas = replicas
+	return nil
 }
 
-func CreateDatabase(cfg *DBConfig) {
-	slashIndex := strings.LastIndex(cfg.DSN, "/")
-	dsn := cfg.DSN[:slashIndex+1]
-	dbName := cfg.DSN[slashIndex+1:]
+func (s *DB) callInjector(db *gorm.DB) error {
+	for _, v := range s.injectors {
+		if err := v(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	dsn = fmt.Sprintf("%s?charset=utf8&parseTime=True&loc=Local", dsn)
-	db, err := gorm.Open(mysql.Open(dsn), nil)
+// 关闭连接池，等待正在执行的查询结束
+func (s *DB) Close() error {
+	idb, err := s.db.DB()
 	if err != nil {
-		log.Fatal(err)
+		return errx.WithStackOnce(err)
 	}
 
-	createSQL := fmt.Sprintf(
-		"CREATE DATABASE IF NOT EXISTS `%s` CHARACTER SET utf8mb4;",
-		dbName,
-	)
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
 
-	err = db.Exec(createSQL).Error
+	closeReplicas(s.replicas)
+	err = idb.Close()
 	if err != nil {
-		log.Fatal(err)
+		return errx.WithStackOnce(err)
 	}
-}
 
-func RegisterInjector(f func(*gorm.DB)) {
-	injectors = append(injectors, f)
+	log.Info("db closed")
+	return nil
 }
 
-func callInjector(db *gorm.DB) {
-	for _, v := range injectors {
-		v(db)
+// 后台导出连接池指标，Close 时停止
+func (s *DB) exportStats() {
+	idb, err := s.db.DB()
+	if err != nil {
+		log.Errorf("export db stats err: %+v", err)
+		return
 	}
+
+	s.stop = make(chan struct{})
+	go exportStats(s.config.Name, idb, s.replicas, s.config.MetricsInterval, s.stop)
 }
 
-type ctxTransactionKey struct{}
+// 检查主库和所有从库的连接，用于就绪检查
+func (s *DB) Ping(ctx context.Context) error {
+	idb, err := s.db.DB()
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
 
-func CtxWithTransaction(ctx context.Context, tx *gorm.DB) context.Context {
-	if ctx == nil {
-		ctx = context.Background()
+	if err := idb.PingContext(ctx); err != nil {
+		return errors2.Wrap(err, "primary")
 	}
-	return context.WithValue(ctx, ctxTransactionKey{}, tx)
-}
 
-type txImpl struct{}
+	for i, v := range s.replicas {
+		if err := v.PingContext(ctx); err != nil {
+			return errors2.Wrapf(err, "replica %d", i)
+		}
+	}
+	return nil
+}
 
-func NewTxImpl() *txImpl {
-	return &txImpl{}
+func (s *DB) Config() DBConfig {
+	return s.config
 }
 
-func (*txImpl) Transaction(ctx context.Context, fn func(txctx context.Context) error) error {
-	db := globalDB.WithContext(ctx)
+// 区分不同数据库的事务
+type ctxTransactionKey struct {
+	db *DB
+}
 
-	return db.Transaction(func(tx *gorm.DB) error {
-		txctx := CtxWithTransaction(ctx, tx)
-		return fn(txctx)
-	})
+func (s *DB) CtxWithTransaction(ctx context.Context, tx *gorm.DB) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxTransactionKey{db: s}, tx)
 }
 
 // 如果使用跨模型事务则传参
-func GetDB(ctx context.Context) *gorm.DB {
-	iface := ctx.Value(ctxTransactionKey{})
-
-	if iface != nil {
-		tx, ok := iface.(*gorm.DB)
-		if !ok {
-			log.Panicf("unexpect context value type: %s", reflect.TypeOf(tx))
-			return nil
-		}
-
+// 配置从库时，事务外的查询走从库，使用 WithPrimary 强制走主库
+func (s *DB) Get(ctx context.Context) *gorm.DB {
+	if tx := s.txFromCtx(ctx); tx != nil {
 		return tx
 	}
 
-	return globalDB.WithContext(ctx)
+	return s.db.WithContext(ctx)
 }
 
-func GetDBConfig() DBConfig {
-	return *globalConfig
-}
+func (s *DB) txFromCtx(ctx context.Context) *gorm.DB {
+	iface := ctx.Value(ctxTransactionKey{db: s})
+	if iface == nil {
+		return nil
+	}
 
-// 自动初始化表结构
-func SetupTableModel(db *gorm.DB, model interface{}) {
-	if GetDBConfig().AutoMigrate {
-		err := db.AutoMigrate(model)
-		if err != nil {
-			log.Fatal(err)
-		}
+	tx, ok := iface.(*gorm.DB)
+	if !ok {
+		log.Panicf("unexpect context value type: %s", reflect.TypeOf(iface))
 	}
+	return tx
 }
 
 // https://github.com/ulid/spec
@@ -166,14 +266,28 @@ func NewUlid() string {
 	return ulid.MustNew(ulid.Timestamp(now), ulid.Monotonic(rand.New(rand.NewSource(now.UnixNano())), 0)).String()
 }
 
-func registerCallback(db *gorm.DB) {
+func registerCallback(db *gorm.DB) error {
 	// 自动添加uuid
 	err := db.Callback().Create().Before("gorm:create").Register("uuid", func(db *gorm.DB) {
-		db.Statement.SetColumn("id", NewUlid())
+		if db.Statement.Schema != nil && db.Statement.Schema.LookUpField("id") != nil {
+			db.Statement.SetColumn("id", NewUlid())
+		}
 	})
 	if err != nil {
-		log.Panicf("err: %+v", errx.WithStackOnce(err))
+		return err
 	}
+
+	// 乐观锁版本号从 1 开始，通过默认值区分其他名为 version 的字段
+	err = db.Callback().Create().Before("gorm:create").Register("version", func(db *gorm.DB) {
+		if db.Statement.Schema == nil {
+			return
+		}
+
+		if f := db.Statement.Schema.LookUpField("version"); f != nil && f.HasDefaultValue {
+			db.Statement.SetColumn("version", 1)
+		}
+	})
+	return err
 }
 
 // tag按首字母排序
@@ -0,0 +1,103 @@
+package dbcore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestConnectRetry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbcore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// 所在目录是普通文件，无法创建数据库
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0644))
+	bad := &DBConfig{
+		Driver: DriverSqlite,
+		DSN:    filepath.Join(file, "test.db"),
+	}
+
+	start := time.Now()
+	_, err = Connect(context.Background(), bad)
+	require.Error(t, err)
+	require.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	bad.ConnectTimeout = 300 * time.Millisecond
+	bad.RetryInterval = 50 * time.Millisecond
+	start = time.Now()
+	_, err = Connect(context.Background(), bad)
+	require.Error(t, err)
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(bad.ConnectTimeout))
+
+	// ctx 取消时停止重试
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bad.ConnectTimeout = time.Minute
+	_, err = Connect(ctx, bad)
+	require.Error(t, err)
+
+	_, err = Connect(context.Background(), &DBConfig{Driver: "unknown"})
+	require.Error(t, err)
+
+	db, err := Connect(context.Background(), &DBConfig{
+		Driver:         DriverSqlite,
+		DSN:            filepath.Join(dir, "test.db"),
+		ConnectTimeout: time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Get(context.Background()).Exec("SELECT 1").Error)
+	require.NoError(t, db.Ping(context.Background()))
+	require.NoError(t, db.Close())
+	require.Error(t, db.Ping(context.Background()))
+	require.Error(t, db.Get(context.Background()).Exec("SELECT 1").Error)
+}
+
+type multiItem struct {
+	Key string `gorm:"primarykey"`
+}
+
+func TestMultipleDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbcore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	connect := func(name string) *DB {
+		db, err := Connect(context.Background(), &DBConfig{
+			Driver: DriverSqlite,
+			DSN:    filepath.Join(dir, name),
+		}, WithInjector(func(db *gorm.DB) error {
+			return db.AutoMigrate(&multiItem{})
+		}))
+		require.NoError(t, err)
+		return db
+	}
+	a, b := connect("a.db"), connect("b.db")
+	defer a.Close()
+	defer b.Close()
+
+	count := func(db *DB, ctx context.Context) int64 {
+		var r int64
+		require.NoError(t, db.Get(ctx).Model(&multiItem{}).Count(&r).Error)
+		return r
+	}
+
+	// a 的事务不影响 b
+	err = a.Transaction(context.Background(), func(txctx context.Context) error {
+		require.NoError(t, a.Get(txctx).Create(&multiItem{Key: "1"}).Error)
+		require.Equal(t, int64(1), count(a, txctx))
+		require.Equal(t, int64(0), count(b, txctx))
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1), count(a, context.Background()))
+	require.Equal(t, int64(0), count(b, context.Background()))
+}
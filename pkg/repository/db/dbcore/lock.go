@@ -2,47 +2,167 @@ package dbcore
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
 	"os"
-	"strings"
+	"sync"
 	"time"
 
+	errors2 "github.com/pkg/errors"
 	log "github.com/win5do/go-lib/logx"
 	"gorm.io/gorm"
 
 	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
 )
 
 const DefaultLeaseAge = 60 * time.Second
 
+// 获取锁失败后的重试间隔，每次翻倍直到上限
+const (
+	lockRetryMin = 100 * time.Millisecond
+	lockRetryMax = 5 * time.Second
+)
+
+type LockMode string
+
+const (
+	LockExclusive LockMode = "exclusive" // 排他锁，同一时间只有一个持有者
+	LockShared    LockMode = "shared"    // 共享锁，可以有多个持有者，与排他锁互斥
+)
+
+// 分布式锁
+//
+// holder 相同时可以重入，包括同一进程中的多个 Locker 和重启后的持有者，每次 Lock 需要对应一次 UnLock。
+type Locker interface {
+	// 尝试一次，锁被其他持有者持有时返回 false
+	Lock() (bool, error)
+	// 阻塞直到获取锁或 ctx 取消
+	LockContext(ctx context.Context) error
+	// 在 timeout 内重试，超时返回 false
+	TryLockFor(timeout time.Duration) (bool, error)
+	UnLock() error
+	// 本次获取锁的 fencing token，同一个 action 单调递增，未持有锁时为 0
+	// 写入外部资源时带上 token，资源方拒绝比已见过的更小的 token，防止租约过期后的旧持有者写入
+	Token() int64
+	// 租约无法在过期前续期或已释放锁时关闭，未持有锁时返回已关闭的 channel
+	Lost() <-chan struct{}
+}
+
+// 每个持有者一行，重入时增加 Holds
 type lock struct {
 	CommonModel
 	ExpiredAt time.Time
-	Action    string `gorm:"unique;not null"`
-	Holder    string
+	Action    string   `gorm:"uniqueIndex:idx_lock_action_holder;size:191;not null"`
+	Holder    string   `gorm:"uniqueIndex:idx_lock_action_holder;size:191;not null"`
+	Mode      LockMode `gorm:"size:16;not null"`
+	Holds     int      `gorm:"not null"` // 重入次数
+	Token     int64
 }
 
+func (lock) TableName() string {
+	return "tb_lock_holders"
+}
+
+// 每个 action 最近一次发放的 token，锁释放后仍保留，保证 token 单调递增
+// 获取锁时先更新这一行，同一个 action 的竞争者在行锁上串行
+type lockToken struct {
+	Action string `gorm:"primarykey"`
+	Token  int64
+}
+
+var closedCh = make(chan struct{})
+
 func init() {
-	RegisterInjector(func(d *gorm.DB) {
-		SetupTableModel(d, &lock{})
-	})
+	close(closedCh)
+}
+
+// 锁被其他持有者持有，回滚事务
+var errLockHeld = errors.New("lock held by others")
+
+type LockOption func(*lockDb)
+
+func WithLockMode(mode LockMode) LockOption {
+	return func(s *lockDb) {
+		s.mode = mode
+	}
 }
 
 type lockDb struct {
 	db       *gorm.DB
-	stopCh   chan struct{}
 	action   string
 	holder   string
+	mode     LockMode
 	leaseAge time.Duration
+
+	mu        sync.Mutex
+	depth     int // 当前实例的重入次数
+	token     int64
+	expiredAt time.Time
+	stopCh    chan struct{}
+	lostCh    chan struct{}
 }
 
-func NewLockDb(action, holder string, lease time.Duration) *lockDb {
-	return &lockDb{
-		db:       GetDB(context.Background()),
-		stopCh:   make(chan struct{}),
+// 默认为排他锁，holder 相同的 Locker 相互重入，需要互斥时使用不同的 holder
+func NewLockDb(db *DB, action, holder string, lease time.Duration, opts ...LockOption) Locker {
+	s := &lockDb{
+		db:       db.Get(WithPrimary(context.Background())),
 		action:   action,
 		holder:   holder,
+		mode:     LockExclusive,
 		leaseAge: lease,
+		lostCh:   closedCh,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// 返回的 ctx 在租约丢失时取消
+func LeaseContext(ctx context.Context, locker Locker) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	lost := locker.Lost()
+	go func() {
+		select {
+		case <-lost:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// 在事务中校验 token 仍然有效，用于受锁保护的写入，过期或已被其他持有者获取时返回 errcode.Err_conflict
+func CheckFencingToken(db *gorm.DB, action string, token int64) error {
+	var count int64
+	err := Primary(db).Model(&lock{}).
+		Where("action = ? AND token = ? AND expired_at > ?", action, token, time.Now()).
+		Count(&count).
+		Error
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
+
+	if count == 0 {
+		return errors2.Wrapf(errcode.Err_conflict, "stale fencing token: %s %d", action, token)
 	}
+
+	return nil
+}
+
+func (s *lockDb) Token() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+func (s *lockDb) Lost() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lostCh
 }
 
 func (s *lockDb) Lock() (bool, error) {
@@ -51,38 +171,189 @@ func (s *lockDb) Lock() (bool, error) {
 		return false, errx.WithStackOnce(err)
 	}
 
-	err = s.db.Create(&lock{
-		ExpiredAt: time.Now().Add(s.leaseAge),
-		Action:    s.action,
-		Holder:    s.holder,
-	}).Error
+	token, expiredAt, err := s.acquire()
 	if err != nil {
-		// Duplicate entry '<action_val>' for key 'action'
-		if strings.Contains(err.Error(), "Duplicate entry") {
+		if errors.Is(err, errLockHeld) || IsUniqueViolation(err) {
 			return false, nil
 		}
 		return false, errx.WithStackOnce(err)
 	}
 
-	s.startLease()
+	// 当前实例已持有时不再启动续期，其他实例持有时 token 与其相同
+	s.mu.Lock()
+	reentrant := s.depth > 0 && s.token != 0
+	if !reentrant {
+		s.depth = 0
+		s.token = token
+		s.lostCh = make(chan struct{})
+	}
+	s.depth++
+	s.expiredAt = expiredAt
+	s.mu.Unlock()
+
+	if !reentrant {
+		s.startLease()
+	}
+
+	log.Debugf("%s get %s lock %s, token: %d", s.holder, s.mode, s.action, token)
+
+	return true, nil
+}
+
+// holder 已持有相同模式的锁时重入，否则按模式判断是否与其他持有者冲突
+//
+// 重入的次数记在同一行，持有者崩溃后留下的次数不会减少，在其他 Locker 全部释放、不再续期后随租约过期清理
+func (s *lockDb) acquire() (int64, time.Time, error) {
+	var token int64
+	expiredAt := time.Now().Add(s.leaseAge)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		next, err := nextToken(tx, s.action)
+		if err != nil {
+			return err
+		}
+
+		var holders []*lock
+		err = tx.Where("action = ? AND expired_at > ?", s.action, time.Now()).Find(&holders).Error
+		if err != nil {
+			return err
+		}
+
+		var mine *lock
+		for _, v := range holders {
+			if v.Holder == s.holder {
+				mine = v
+				continue
+			}
+
+			if s.mode == LockExclusive || v.Mode == LockExclusive {
+				return errLockHeld
+			}
+		}
+
+		if mine != nil {
+			// 不支持升级或降级
+			if mine.Mode != s.mode {
+				return errLockHeld
+			}
+
+			token = mine.Token
+			return tx.Model(mine).Updates(map[string]interface{}{
+				"holds":      gorm.Expr("holds + 1"),
+				"expired_at": expiredAt,
+			}).Error
+		}
+
+		token = next
+		return tx.Create(&lock{
+			ExpiredAt: expiredAt,
+			Action:    s.action,
+			Holder:    s.holder,
+			Mode:      s.mode,
+			Holds:     1,
+			Token:     next,
+		}).Error
+	})
+
+	return token, expiredAt, err
+}
+
+// 递增 token 同时锁住该 action 的 token 行
+func nextToken(tx *gorm.DB, action string) (int64, error) {
+	r := tx.Model(&lockToken{}).Where("action = ?", action).Update("token", gorm.Expr("token + 1"))
+	if r.Error != nil {
+		return 0, r.Error
+	}
+
+	// 首次获取，并发插入时其中一个会唯一索引冲突
+	if r.RowsAffected == 0 {
+		t := &lockToken{Action: action, Token: 1}
+		return t.Token, tx.Create(t).Error
+	}
+
+	var t lockToken
+	err := tx.Where("action = ?", action).First(&t).Error
+	return t.Token, err
+}
 
-	log.Debugf("%s get lock", s.holder)
+func (s *lockDb) LockContext(ctx context.Context) error {
+	wait := lockRetryMin
+	for {
+		ok, err := s.Lock()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		// 加入随机抖动，避免多个副本同时重试
+		timer := time.NewTimer(wait/2 + time.Duration(rand.Int63n(int64(wait/2))))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errx.WithStackOnce(ctx.Err())
+		}
+
+		wait *= 2
+		if wait > lockRetryMax {
+			wait = lockRetryMax
+		}
+	}
+}
+
+func (s *lockDb) TryLockFor(timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := s.LockContext(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return false, nil
+		}
+		return false, err
+	}
 
 	return true, nil
 }
 
+// 重入时只减少次数，最后一次释放时删除记录
 func (s *lockDb) UnLock() error {
-	s.stopLease()
-	var err error
+	s.mu.Lock()
+	if s.depth == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	s.depth--
+	last := s.depth == 0
+	token := s.token
+	s.mu.Unlock()
+
+	if last {
+		s.stopLease()
+	}
 
-	defer func() {
-		err = s.db.
-			Where("action = ? and holder = ?", s.action, s.holder).
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&lock{}).
+			Where("action = ? and holder = ? and token = ?", s.action, s.holder, token).
+			Update("holds", gorm.Expr("holds - 1")).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Where("action = ? and holder = ? and holds <= 0", s.action, s.holder).
 			Delete(&lock{}).
 			Error
-	}()
+	})
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
 
-	return err
+	return nil
 }
 
 func (s *lockDb) cleanExpired() error {
@@ -95,19 +366,37 @@ func (s *lockDb) cleanExpired() error {
 }
 
 func (s *lockDb) startLease() {
+	stopCh := make(chan struct{})
+	s.mu.Lock()
+	s.stopCh = stopCh
+	s.mu.Unlock()
+
 	go func() {
-		// 剩余 1/4 时刷新租约
-		ticker := time.NewTicker(s.leaseAge * 3 / 4)
+		// 每 1/3 租约刷新一次，失败时在过期前还有重试的机会
+		interval := s.leaseAge / 3
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
-				err := s.refreshLease()
-				if err != nil {
-					log.Errorf("refreash lease err: %s", err)
-				} else {
+				ok, err := s.refreshLease()
+				if err == nil && ok {
 					log.Debug("lease refreshed")
+					continue
+				}
+
+				// 出错时继续重试，锁已被其他持有者获取或被强制释放时直接放弃
+				// 下次重试前租约可能已经过期时也放弃，预留 1/10 租约应对时钟误差和请求延迟，保证在过期前通知
+				if err != nil {
+					log.Errorf("refresh lease err: %s", err)
+				}
+
+				if err == nil || time.Until(s.leaseExpiredAt()) < interval+s.leaseAge/10 {
+					log.Errorf("lease lost: %s, holder: %s", s.action, s.holder)
+					s.stopLease()
+					return
 				}
-			case <-s.stopCh:
+			case <-stopCh:
 				log.Debug("lease stopped")
 				return
 			}
@@ -115,17 +404,53 @@ func (s *lockDb) startLease() {
 	}()
 }
 
+func (s *lockDb) leaseExpiredAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expiredAt
+}
+
+// 停止续期并通知租约丢失，未获取到锁时 stopCh 为空
 func (s *lockDb) stopLease() {
-	close(s.stopCh)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.stopCh = nil
+	}
+
+	if s.lostCh != closedCh {
+		close(s.lostCh)
+		s.lostCh = closedCh
+	}
+	s.token = 0
 }
 
-func (s *lockDb) refreshLease() error {
-	err := s.db.Model(&lock{}).
-		Where("action = ? and holder = ?", s.action, s.holder).
-		Update("expired_at", time.Now().Add(s.leaseAge)).
-		Error
+// 只有 token 一致时才续期，锁已被其他持有者获取时返回 false
+func (s *lockDb) refreshLease() (bool, error) {
+	token := s.Token()
+	expiredAt := time.Now().Add(s.leaseAge)
+	r := s.db.Model(&lock{}).
+		Where("action = ? and holder = ? and token = ?", s.action, s.holder, token).
+		Update("expired_at", expiredAt)
+	if r.Error != nil {
+		return false, r.Error
+	}
 
-	return err
+	if r.RowsAffected == 0 {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	s.expiredAt = expiredAt
+	s.mu.Unlock()
+	return true, nil
+}
+
+// 主机名加进程号，同一主机上的多个进程也需要互斥时作为 holder
+func GetProcessIdentity() string {
+	return fmt.Sprintf("%s-%d", GetHostname(), os.Getpid())
 }
 
 func GetHostname() string {
@@ -0,0 +1,71 @@
+package dbcore
+
+import (
+	"context"
+	"time"
+
+	"github.com/win5do/go-lib/errx"
+)
+
+type LockInfo struct {
+	Action    string
+	Holder    string
+	Mode      LockMode
+	Holds     int
+	Token     int64
+	CreatedAt time.Time
+	ExpiredAt time.Time
+}
+
+// 锁管理，用于排查和处理卡住的锁
+type lockAdmin struct {
+	db *DB
+}
+
+func NewLockAdmin(db *DB) *lockAdmin {
+	return &lockAdmin{db: db}
+}
+
+// 列出未过期的锁，action 为空时返回全部
+func (s *lockAdmin) ListLocks(ctx context.Context, action string) ([]*LockInfo, error) {
+	var locks []*lock
+	db := s.db.Get(WithPrimary(ctx)).Where("expired_at > ?", time.Now())
+	if action != "" {
+		db = db.Where("action = ?", action)
+	}
+
+	err := db.Order("action").Order("holder").Find(&locks).Error
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	r := make([]*LockInfo, 0, len(locks))
+	for _, v := range locks {
+		r = append(r, &LockInfo{
+			Action:    v.Action,
+			Holder:    v.Holder,
+			Mode:      v.Mode,
+			Holds:     v.Holds,
+			Token:     v.Token,
+			CreatedAt: v.CreatedAt,
+			ExpiredAt: v.ExpiredAt,
+		})
+	}
+	return r, nil
+}
+
+// 强制释放 action 的所有持有者，holder 不为空时只释放该持有者，返回释放的个数
+// 持有者在下次续期时发现锁已丢失
+func (s *lockAdmin) ForceUnlock(ctx context.Context, action, holder string) (int64, error) {
+	db := s.db.Get(ctx).Where("action = ?", action)
+	if holder != "" {
+		db = db.Where("holder = ?", holder)
+	}
+
+	r := db.Delete(&lock{})
+	if r.Error != nil {
+		return 0, errx.WithStackOnce(r.Error)
+	}
+
+	return r.RowsAffected, nil
+}
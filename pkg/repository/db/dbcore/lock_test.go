@@ -0,0 +1,54 @@
+package dbcore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newLockTestDB(t *testing.T) (*DB, func()) {
+	dir, err := ioutil.TempDir("", "dbcore")
+	require.NoError(t, err)
+
+	db, err := Connect(context.Background(), &DBConfig{
+		Driver: DriverSqlite,
+		DSN:    filepath.Join(dir, "test.db"),
+	}, WithInjector(func(db *gorm.DB) error {
+		return db.AutoMigrate(&lock{}, &lockToken{})
+	}))
+	require.NoError(t, err)
+
+	return db, func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// 续期一直失败时，在租约过期前关闭 Lost
+func TestLeaseLost(t *testing.T) {
+	db, cleanup := newLockTestDB(t)
+	defer cleanup()
+
+	locker := NewLockDb(db, "test", "a", 600*time.Millisecond).(*lockDb)
+	ok, err := locker.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	lost := locker.Lost()
+	expiredAt := locker.leaseExpiredAt()
+
+	// 删除锁表使续期出错
+	require.NoError(t, db.Get(context.Background()).Migrator().DropTable(&lock{}))
+
+	select {
+	case <-lost:
+		require.True(t, time.Now().Before(expiredAt))
+	case <-time.After(time.Second):
+		t.Fatal("lease not lost")
+	}
+}
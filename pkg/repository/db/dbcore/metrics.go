@@ -0,0 +1,178 @@
+package dbcore
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+)
+
+// 数据库指标，通过 RegisterMetrics 注册后暴露
+//
+// 标签 db 为 DBConfig.Name，区分同一进程中的多个数据库；pool 为 primary 或 replica-N。
+
+var (
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of db queries by table and operation.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"db", "table", "operation"})
+
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Failed db queries by table and operation, record not found excluded.",
+	}, []string{"db", "table", "operation"})
+
+	dbConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_connections",
+		Help: "Connections of db pool by state: open, in_use, idle.",
+	}, []string{"db", "pool", "state"})
+
+	dbMaxOpenConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_max_open_connections",
+		Help: "Maximum number of open connections of db pool.",
+	}, []string{"db", "pool"})
+
+	dbWaitCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_wait_count_total",
+		Help: "Connections waited for because the db pool is exhausted.",
+	}, []string{"db", "pool"})
+
+	dbWaitDuration = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_wait_duration_seconds_total",
+		Help: "Time blocked waiting for a new connection.",
+	}, []string{"db", "pool"})
+)
+
+// 所有数据库共用一组指标，只需要注册一次
+func RegisterMetrics(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		dbQueryDuration,
+		dbQueryErrors,
+		dbConnections,
+		dbMaxOpenConnections,
+		dbWaitCount,
+		dbWaitDuration,
+	}
+	for _, v := range collectors {
+		if err := reg.Register(v); err != nil {
+			return errx.WithStackOnce(err)
+		}
+	}
+	return nil
+}
+
+const metricsStartKey = "metrics:start"
+
+// gorm 插件，记录每个查询的耗时和错误，超过 slowThreshold 的查询打印日志
+type metricsPlugin struct {
+	name          string
+	slowThreshold time.Duration
+}
+
+func (s *metricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (s *metricsPlugin) Initialize(db *gorm.DB) error {
+	type register func(name string, fn func(*gorm.DB)) error
+
+	cb := db.Callback()
+	processors := []struct {
+		operation     string
+		before, after register
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+
+	for _, v := range processors {
+		if err := v.before("metrics:before", s.before); err != nil {
+			return err
+		}
+		if err := v.after("metrics:after", s.after(v.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *metricsPlugin) before(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func (s *metricsPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		cost := time.Since(start)
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		dbQueryDuration.WithLabelValues(s.name, table, operation).Observe(cost.Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			dbQueryErrors.WithLabelValues(s.name, table, operation).Inc()
+		}
+
+		if s.slowThreshold > 0 && cost > s.slowThreshold {
+			log.Infof("slow query: %s, cost: %s, rows: %d, sql: %s",
+				s.name, cost, db.RowsAffected, db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...))
+		}
+	}
+}
+
+// 定期导出连接池状态，直到 stop 关闭
+func exportStats(name string, primary *sql.DB, replicas []*sql.DB, interval time.Duration, stop <-chan struct{}) {
+	pools := map[string]*sql.DB{"primary": primary}
+	for i, v := range replicas {
+		pools["replica-"+strconv.Itoa(i)] = v
+	}
+
+	// 连接池的等待次数和时长是累计值，按增量计入 counter
+	last := map[string]sql.DBStats{}
+	collect := func() {
+		for pool, db := range pools {
+			stats := db.Stats()
+			dbConnections.WithLabelValues(name, pool, "open").Set(float64(stats.OpenConnections))
+			dbConnections.WithLabelValues(name, pool, "in_use").Set(float64(stats.InUse))
+			dbConnections.WithLabelValues(name, pool, "idle").Set(float64(stats.Idle))
+			dbMaxOpenConnections.WithLabelValues(name, pool).Set(float64(stats.MaxOpenConnections))
+			dbWaitCount.WithLabelValues(name, pool).Add(float64(stats.WaitCount - last[pool].WaitCount))
+			dbWaitDuration.WithLabelValues(name, pool).Add((stats.WaitDuration - last[pool].WaitDuration).Seconds())
+			last[pool] = stats
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	collect()
+	for {
+		select {
+		case <-ticker.C:
+			collect()
+		case <-stop:
+			return
+		}
+	}
+}
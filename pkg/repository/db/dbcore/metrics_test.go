@@ -0,0 +1,79 @@
+package dbcore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// 读取直方图的样本数
+func histogramCount(t *testing.T, g prometheus.Gatherer, name string, labels map[string]string) uint64 {
+	families, err := g.Gather()
+	require.NoError(t, err)
+
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	next:
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if v, ok := labels[l.GetName()]; ok && v != l.GetValue() {
+					continue next
+				}
+			}
+			return m.GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	require.NoError(t, RegisterMetrics(reg))
+
+	dir, err := ioutil.TempDir("", "dbcore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := Connect(context.Background(), &DBConfig{
+		Name:            "metrics-test",
+		Driver:          DriverSqlite,
+		DSN:             filepath.Join(dir, "test.db"),
+		SlowThreshold:   time.Nanosecond,
+		MetricsInterval: 10 * time.Millisecond,
+	}, WithInjector(func(db *gorm.DB) error {
+		return db.AutoMigrate(&multiItem{})
+	}))
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	labels := func(operation string) map[string]string {
+		return map[string]string{"db": "metrics-test", "table": "tb_multi_items", "operation": operation}
+	}
+
+	require.NoError(t, db.Get(ctx).Create(&multiItem{Key: "1"}).Error)
+	require.Equal(t, uint64(1), histogramCount(t, reg, "db_query_duration_seconds", labels("create")))
+
+	// 未找到不计入错误
+	require.Error(t, db.Get(ctx).First(&multiItem{}, "key = ?", "2").Error)
+	require.Equal(t, uint64(1), histogramCount(t, reg, "db_query_duration_seconds", labels("query")))
+	require.Equal(t, float64(0), testutil.ToFloat64(dbQueryErrors.WithLabelValues("metrics-test", "tb_multi_items", "query")))
+
+	// 主键冲突
+	require.Error(t, db.Get(ctx).Create(&multiItem{Key: "1"}).Error)
+	require.Equal(t, float64(1), testutil.ToFloat64(dbQueryErrors.WithLabelValues("metrics-test", "tb_multi_items", "create")))
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(dbMaxOpenConnections.WithLabelValues("metrics-test", "primary")) == 20
+	}, time.Second, 10*time.Millisecond)
+}
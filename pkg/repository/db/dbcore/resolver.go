@@ -0,0 +1,119 @@
+package dbcore
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/win5do/go-lib/errx"
+)
+
+// 读写分离
+//
+// 配置从库后，事务外的查询随机发往从库，写入、事务和加锁查询（FOR UPDATE）都走主库。
+// 从库有复制延迟，写入后需要读到最新数据时使用 WithPrimary，结构迁移也需要在主库上执行。
+
+type ctxPrimaryKey struct{}
+
+// 强制 ctx 内的查询走主库
+func WithPrimary(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxPrimaryKey{}, true)
+}
+
+func isPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(ctxPrimaryKey{}).(bool)
+	return v
+}
+
+// 返回强制走主库的 db，db 为事务时不变
+func Primary(db *gorm.DB) *gorm.DB {
+	return db.WithContext(WithPrimary(db.Statement.Context))
+}
+
+func openReplicas(cfg *DBConfig) ([]*sql.DB, error) {
+	var r []*sql.DB
+	for _, dsn := range cfg.ReplicaDSNs {
+		dial, err := dialector(cfg.Driver, dsn)
+		if err != nil {
+			closeReplicas(r)
+			return nil, err
+		}
+
+		db, err := gorm.Open(dial, &gorm.Config{})
+		if err != nil {
+			closeReplicas(r)
+			return nil, errx.WithStackOnce(err)
+		}
+
+		idb, err := db.DB()
+		if err != nil {
+			closeReplicas(r)
+			return nil, errx.WithStackOnce(err)
+		}
+		idb.SetMaxIdleConns(cfg.MaxIdleConns)
+		idb.SetMaxOpenConns(cfg.MaxOpenConns)
+
+		r = append(r, idb)
+	}
+
+	return r, nil
+}
+
+func closeReplicas(replicas []*sql.DB) {
+	for _, v := range replicas {
+		_ = v.Close()
+	}
+}
+
+// 在查询前切换连接池
+func registerResolver(db *gorm.DB, replicas []*sql.DB) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	resolve := func(db *gorm.DB) {
+		if !useReplica(db) {
+			return
+		}
+		db.Statement.ConnPool = replicas[rand.Intn(len(replicas))]
+	}
+
+	err := db.Callback().Query().Before("*").Register("replica", resolve)
+	if err != nil {
+		return err
+	}
+
+	return db.Callback().Row().Before("*").Register("replica", resolve)
+}
+
+func useReplica(db *gorm.DB) bool {
+	// 事务中
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return false
+	}
+
+	if isPrimary(db.Statement.Context) {
+		return false
+	}
+
+	if _, ok := db.Statement.Clauses["FOR"]; ok {
+		return false
+	}
+
+	// Raw 语句只有 SELECT 走从库
+	if raw := strings.TrimSpace(db.Statement.SQL.String()); raw != "" {
+		return len(raw) > 6 && strings.EqualFold(raw[:6], "select") &&
+			!strings.HasSuffix(strings.ToLower(raw), "for update")
+	}
+
+	return true
+}
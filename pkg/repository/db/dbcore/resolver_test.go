@@ -0,0 +1,72 @@
+package dbcore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type resolverItem struct {
+	Key  string `gorm:"primarykey"`
+	Name string
+}
+
+func TestResolver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resolver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// 用两个独立的库模拟主从，从库的数据不同
+	replicaDSN := filepath.Join(dir, "replica.db")
+	replica, err := gorm.Open(dialectorOrDie(t, replicaDSN), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, replica.Table("tb_resolver_items").AutoMigrate(&resolverItem{}))
+	require.NoError(t, replica.Table("tb_resolver_items").Create(&resolverItem{Key: "1", Name: "replica"}).Error)
+
+	db, err := Connect(context.Background(), &DBConfig{
+		Driver:      DriverSqlite,
+		DSN:         filepath.Join(dir, "primary.db"),
+		ReplicaDSNs: []string{replicaDSN},
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	require.NoError(t, Primary(db.Get(ctx)).AutoMigrate(&resolverItem{}))
+	require.NoError(t, db.Get(ctx).Create(&resolverItem{Key: "1", Name: "primary"}).Error)
+
+	get := func(db *gorm.DB) string {
+		var r resolverItem
+		require.NoError(t, db.First(&r, "key = ?", "1").Error)
+		return r.Name
+	}
+
+	require.Equal(t, "replica", get(db.Get(ctx)))
+	require.Equal(t, "primary", get(db.Get(WithPrimary(ctx))))
+	require.Equal(t, "primary", get(Primary(db.Get(ctx))))
+
+	var name string
+	require.NoError(t, db.Get(ctx).Raw("SELECT name FROM tb_resolver_items WHERE key = ?", "1").Scan(&name).Error)
+	require.Equal(t, "replica", name)
+
+	err = db.Transaction(ctx, func(txctx context.Context) error {
+		require.Equal(t, "primary", get(db.Get(txctx)))
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Get(ctx).Exec("UPDATE tb_resolver_items SET name = ? WHERE key = ?", "updated", "1").Error)
+	require.Equal(t, "updated", get(db.Get(WithPrimary(ctx))))
+	require.Equal(t, "replica", get(db.Get(ctx)))
+}
+
+func dialectorOrDie(t *testing.T, dsn string) gorm.Dialector {
+	dial, err := dialector(DriverSqlite, dsn)
+	require.NoError(t, err)
+	return dial
+}
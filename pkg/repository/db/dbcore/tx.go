@@ -0,0 +1,129 @@
+package dbcore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	errors2 "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+)
+
+// 重试前的最大等待，实际等待随机，避免冲突的事务同时重试
+const txRetryMaxWait = 100 * time.Millisecond
+
+// 实现 model.ITransaction
+//
+// ctx 中已有本数据库的事务时复用，用 SAVEPOINT 隔离，fn 失败只回滚到 savepoint。
+// 最外层事务遇到死锁或序列化失败时整体重试，fn 可能执行多次，不要在其中产生事务外的副作用。
+func (s *DB) Transaction(ctx context.Context, fn func(txctx context.Context) error, opts ...model.TxOption) error {
+	o := model.NewTxOptions(opts...)
+	ctx, cancel := o.Context(ctx)
+	defer cancel()
+
+	if tx := s.txFromCtx(ctx); tx != nil {
+		return s.nested(ctx, tx, fn)
+	}
+
+	txOpts := &sql.TxOptions{
+		Isolation: o.Isolation,
+		ReadOnly:  o.ReadOnly,
+	}
+
+	for i := 0; ; i++ {
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(s.CtxWithTransaction(ctx, tx))
+		}, txOpts)
+		if err == nil || i >= s.config.TxMaxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		wait := time.Duration(rand.Int63n(int64(txRetryMaxWait)))
+		log.Infof("transaction aborted: %s, retry %d in %s", err, i+1, wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors2.Wrapf(err, "retry transaction: %s", ctx.Err())
+		}
+	}
+}
+
+// mysql、postgres、sqlite 都支持标准的 SAVEPOINT 语法
+// 不使用 gorm 的嵌套事务：savepoint 以 fn 的地址命名，递归调用时重名，且 sqlite 驱动忽略了执行错误
+// 结束后释放 savepoint，避免长事务中不断累积，ROLLBACK TO 不会释放，回滚后同样需要释放
+func (s *DB) nested(ctx context.Context, tx *gorm.DB, fn func(txctx context.Context) error) (err error) {
+	name := fmt.Sprintf("sp%d", atomic.AddUint64(&s.savepoint, 1))
+
+	if err := tx.Exec("SAVEPOINT " + name).Error; err != nil {
+		return errx.WithStackOnce(err)
+	}
+
+	panicked := true
+	defer func() {
+		if panicked || err != nil {
+			if rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error; rbErr != nil {
+				log.Errorf("rollback to savepoint %s err: %s", name, rbErr)
+				return
+			}
+		}
+
+		// panic 时外层事务整体回滚，不需要释放
+		if panicked {
+			return
+		}
+
+		if rlErr := tx.Exec("RELEASE SAVEPOINT " + name).Error; rlErr != nil {
+			log.Errorf("release savepoint %s err: %s", name, rlErr)
+			if err == nil {
+				err = errx.WithStackOnce(rlErr)
+			}
+		}
+	}()
+
+	err = fn(ctx)
+	panicked = false
+	return err
+}
+
+// 死锁或序列化失败，事务已被数据库回滚，可以整体重试
+func IsRetryable(err error) bool {
+	var myErr *mysqldriver.MySQLError
+	if errors2.As(err, &myErr) {
+		// ER_LOCK_DEADLOCK
+		return myErr.Number == 1213
+	}
+
+	var pgErr *pgconn.PgError
+	if errors2.As(err, &pgErr) {
+		// serialization_failure, deadlock_detected
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+
+	return false
+}
+
+type txImpl struct {
+	db *DB
+}
+
+func NewTxImpl(db *DB) *txImpl {
+	return &txImpl{db: db}
+}
+
+func (s *txImpl) Transaction(ctx context.Context, fn func(txctx context.Context) error, opts ...model.TxOption) error {
+	return s.db.Transaction(ctx, fn, opts...)
+}
@@ -0,0 +1,195 @@
+package dbcore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	errors2 "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+)
+
+func newTxTestDB(t *testing.T, retries int) (*DB, func()) {
+	dir, err := ioutil.TempDir("", "dbcore")
+	require.NoError(t, err)
+
+	db, err := Connect(context.Background(), &DBConfig{
+		Driver:       DriverSqlite,
+		DSN:          filepath.Join(dir, "test.db"),
+		TxMaxRetries: retries,
+	}, WithInjector(func(db *gorm.DB) error {
+		return db.AutoMigrate(&multiItem{})
+	}))
+	require.NoError(t, err)
+
+	return db, func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func keys(t *testing.T, db *DB) []string {
+	var r []string
+	require.NoError(t, db.Get(context.Background()).Model(&multiItem{}).Order("key").Pluck("key", &r).Error)
+	return r
+}
+
+func TestNestedTransaction(t *testing.T) {
+	db, cleanup := newTxTestDB(t, 0)
+	defer cleanup()
+
+	create := func(ctx context.Context, key string) {
+		require.NoError(t, db.Get(ctx).Create(&multiItem{Key: key}).Error)
+	}
+
+	err := db.Transaction(context.Background(), func(txctx context.Context) error {
+		create(txctx, "1")
+
+		// 内层失败只回滚到 savepoint
+		err := db.Transaction(txctx, func(txctx context.Context) error {
+			create(txctx, "2")
+			return errors2.New("inner")
+		})
+		require.Error(t, err)
+
+		// 多层嵌套
+		return db.Transaction(txctx, func(txctx context.Context) error {
+			create(txctx, "3")
+			return db.Transaction(txctx, func(txctx context.Context) error {
+				create(txctx, "4")
+				return nil
+			})
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "3", "4"}, keys(t, db))
+
+	// 内层 panic 回滚到 savepoint 后继续抛出，外层整体回滚
+	require.Panics(t, func() {
+		_ = db.Transaction(context.Background(), func(txctx context.Context) error {
+			create(txctx, "5")
+			return db.Transaction(txctx, func(txctx context.Context) error {
+				panic("inner")
+			})
+		})
+	})
+	require.Equal(t, []string{"1", "3", "4"}, keys(t, db))
+}
+
+// 嵌套事务结束后释放 savepoint，不在外层事务中累积
+func TestReleaseSavepoint(t *testing.T) {
+	db, cleanup := newTxTestDB(t, 0)
+	defer cleanup()
+
+	released := func(txctx context.Context) {
+		name := fmt.Sprintf("sp%d", atomic.LoadUint64(&db.savepoint))
+		require.Error(t, db.Get(txctx).Exec("RELEASE SAVEPOINT "+name).Error)
+	}
+
+	err := db.Transaction(context.Background(), func(txctx context.Context) error {
+		require.NoError(t, db.Transaction(txctx, func(txctx context.Context) error {
+			return db.Get(txctx).Create(&multiItem{Key: "1"}).Error
+		}))
+		released(txctx)
+
+		require.Error(t, db.Transaction(txctx, func(txctx context.Context) error {
+			return errors2.New("inner")
+		}))
+		released(txctx)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, keys(t, db))
+}
+
+func TestTransactionRetry(t *testing.T) {
+	db, cleanup := newTxTestDB(t, 2)
+	defer cleanup()
+
+	deadlock := &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	// 前两次死锁，第三次成功，失败的尝试都已回滚
+	calls := 0
+	err := db.Transaction(context.Background(), func(txctx context.Context) error {
+		calls++
+		require.NoError(t, db.Get(txctx).Create(&multiItem{Key: "1"}).Error)
+		if calls < 3 {
+			return errors2.WithStack(deadlock)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+	require.Equal(t, []string{"1"}, keys(t, db))
+
+	// 超过重试次数
+	calls = 0
+	err = db.Transaction(context.Background(), func(txctx context.Context) error {
+		calls++
+		return deadlock
+	})
+	require.Error(t, err)
+	require.Equal(t, 3, calls)
+
+	// 其他错误不重试
+	calls = 0
+	err = db.Transaction(context.Background(), func(txctx context.Context) error {
+		calls++
+		return errors2.New("other")
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+
+	// 嵌套事务不重试，由最外层重试
+	calls = 0
+	err = db.Transaction(context.Background(), func(txctx context.Context) error {
+		return db.Transaction(txctx, func(txctx context.Context) error {
+			calls++
+			return deadlock
+		})
+	})
+	require.Error(t, err)
+	require.Equal(t, 3, calls)
+}
+
+func TestTransactionOptions(t *testing.T) {
+	db, cleanup := newTxTestDB(t, 0)
+	defer cleanup()
+
+	err := db.Transaction(context.Background(), func(txctx context.Context) error {
+		deadline, ok := txctx.Deadline()
+		require.True(t, ok)
+		require.True(t, time.Until(deadline) <= time.Second)
+		return db.Get(txctx).Create(&multiItem{Key: "1"}).Error
+	}, model.WithTxTimeout(time.Second), model.WithIsolation(0))
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, keys(t, db))
+
+	// 超时后 fn 中的查询失败，事务回滚
+	err = db.Transaction(context.Background(), func(txctx context.Context) error {
+		<-txctx.Done()
+		return db.Get(txctx).Create(&multiItem{Key: "2"}).Error
+	}, model.WithTxTimeout(10*time.Millisecond))
+	require.Error(t, err)
+	require.Equal(t, []string{"1"}, keys(t, db))
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.True(t, IsRetryable(errors2.WithStack(&mysqldriver.MySQLError{Number: 1213})))
+	require.False(t, IsRetryable(&mysqldriver.MySQLError{Number: 1062}))
+	require.True(t, IsRetryable(&pgconn.PgError{Code: "40001"}))
+	require.True(t, IsRetryable(&pgconn.PgError{Code: "40P01"}))
+	require.False(t, IsRetryable(&pgconn.PgError{Code: "23505"}))
+	require.False(t, IsRetryable(errors2.New("other")))
+	require.False(t, IsRetryable(nil))
+}
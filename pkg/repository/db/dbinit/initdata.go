@@ -1,6 +1,8 @@
 package dbinit
 
 import (
+	"context"
+
 	log "github.com/win5do/go-lib/logx"
 
 	"github.com/win5do/go-lib/errx"
@@ -8,25 +10,36 @@ import (
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 )
 
-func InitData() error {
-	locker := dbcore.NewLockDb("init", dbcore.GetHostname(), dbcore.DefaultLeaseAge)
-	ok, err := locker.Lock()
-	if err != nil {
-		return errx.WithStackOnce(err)
+// 等待其他副本初始化完成，返回时数据已初始化
+// seedDir 不为空时执行其中未执行过的种子
+func InitData(ctx context.Context, db *dbcore.DB, seedDir string) error {
+	var seeds []*Seed
+	if seedDir != "" {
+		var err error
+		seeds, err = LoadSeeds(seedDir)
+		if err != nil {
+			return err
+		}
 	}
 
-	if !ok {
-		return nil
+	return withLock(ctx, db, func() error {
+		log.Infof("%s begin init data", dbcore.GetHostname())
+		_, err := ApplySeeds(db.Get(ctx), seeds, false)
+		return err
+	})
+}
+
+// 本机的其他进程同样互斥，如与服务同时执行的 seed 命令
+func withLock(ctx context.Context, db *dbcore.DB, fn func() error) error {
+	locker := dbcore.NewLockDb(db, "init", dbcore.GetProcessIdentity(), dbcore.DefaultLeaseAge)
+	err := locker.LockContext(ctx)
+	if err != nil {
+		return errx.WithStackOnce(err)
 	}
 
 	defer func() {
 		_ = locker.UnLock()
 	}()
 
-	return run()
-}
-
-func run() error {
-	log.Infof("%s begin init data", dbcore.GetHostname())
-	return nil
+	return fn()
 }
@@ -0,0 +1,31 @@
+package dbinit
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+)
+
+// 迁移中使用表结构的快照，不引用 seedVersion
+type seedVersionV1 struct {
+	Version   string `gorm:"primarykey;size:191"`
+	AppliedAt time.Time
+}
+
+func (seedVersionV1) TableName() string { return "tb_seed_versions" }
+
+func init() {
+	migration.Register(&migration.Migration{
+		Version:  2021010202,
+		Name:     "create seed version table",
+		Checksum: "1",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&seedVersionV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&seedVersionV1{})
+		},
+	})
+}
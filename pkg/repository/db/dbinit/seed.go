@@ -0,0 +1,327 @@
+package dbinit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gorm"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+// 一个种子文件，支持 yaml 和 json
+//
+// 以名称作为自然键，已存在的记录更新为文件中的值，不存在则创建，重复执行结果一致
+type Seed struct {
+	// 为空时使用文件名（不含扩展名），按字典序执行
+	Version    string       `json:"version" yaml:"version"`
+	Owners     []*OwnerSeed `json:"owners" yaml:"owners"`
+	Pets       []*PetSeed   `json:"pets" yaml:"pets"`
+	Ownerships []*Ownership `json:"ownerships" yaml:"ownerships"`
+}
+
+type OwnerSeed struct {
+	Name  string `json:"name" yaml:"name"`
+	Age   uint32 `json:"age" yaml:"age"`
+	Sex   string `json:"sex" yaml:"sex"`
+	Phone string `json:"phone" yaml:"phone"`
+}
+
+type PetSeed struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+	Age  uint32 `json:"age" yaml:"age"`
+	Sex  string `json:"sex" yaml:"sex"`
+}
+
+// 通过名称引用 owner 和 pet，可以引用之前种子创建的记录
+type Ownership struct {
+	Owner string `json:"owner" yaml:"owner"`
+	Pet   string `json:"pet" yaml:"pet"`
+}
+
+// 种子执行记录
+type seedVersion struct {
+	Version   string `gorm:"primarykey;size:191"`
+	AppliedAt time.Time
+}
+
+// 与 yaml.UnmarshalStrict 一致，未知字段报错
+func unmarshalJSONStrict(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+
+// 读取目录下的 .yaml、.yml 和 .json 文件，按版本排序
+func LoadSeeds(dir string) ([]*Seed, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	var r []*Seed
+	versions := map[string]string{}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(f.Name())
+		var unmarshal func([]byte, interface{}) error
+		switch strings.ToLower(ext) {
+		case ".yaml", ".yml":
+			unmarshal = yaml.UnmarshalStrict
+		case ".json":
+			unmarshal = unmarshalJSONStrict
+		default:
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errx.WithStackOnce(err)
+		}
+
+		seed := &Seed{}
+		if err := unmarshal(data, seed); err != nil {
+			return nil, errors2.Wrapf(err, "parse seed %s", f.Name())
+		}
+
+		if seed.Version == "" {
+			seed.Version = strings.TrimSuffix(f.Name(), ext)
+		}
+
+		if other, ok := versions[seed.Version]; ok {
+			return nil, errors2.Errorf("duplicate seed version %s: %s, %s", seed.Version, other, f.Name())
+		}
+		versions[seed.Version] = f.Name()
+
+		if err := seed.validate(); err != nil {
+			return nil, errors2.Wrapf(err, "seed %s", f.Name())
+		}
+
+		r = append(r, seed)
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Version < r[j].Version
+	})
+	return r, nil
+}
+
+func (s *Seed) validate() error {
+	owners := map[string]bool{}
+	for _, v := range s.Owners {
+		if v.Name == "" {
+			return errors2.New("owner name is required")
+		}
+		if owners[v.Name] {
+			return errors2.Errorf("duplicate owner: %s", v.Name)
+		}
+		owners[v.Name] = true
+	}
+
+	pets := map[string]bool{}
+	for _, v := range s.Pets {
+		if v.Name == "" {
+			return errors2.New("pet name is required")
+		}
+		if pets[v.Name] {
+			return errors2.Errorf("duplicate pet: %s", v.Name)
+		}
+		pets[v.Name] = true
+	}
+
+	for _, v := range s.Ownerships {
+		if v.Owner == "" || v.Pet == "" {
+			return errors2.New("ownership owner and pet are required")
+		}
+	}
+
+	return nil
+}
+
+// 执行未执行过的种子，force 为 true 时全部重新执行，返回执行的个数
+func ApplySeeds(db *gorm.DB, seeds []*Seed, force bool) (int, error) {
+	if len(seeds) == 0 {
+		return 0, nil
+	}
+	db = dbcore.Primary(db)
+
+	var done []*seedVersion
+	err := db.Find(&done).Error
+	if err != nil {
+		return 0, errx.WithStackOnce(err)
+	}
+
+	applied := make(map[string]bool, len(done))
+	for _, v := range done {
+		applied[v.Version] = true
+	}
+
+	var count int
+	for _, seed := range seeds {
+		if applied[seed.Version] && !force {
+			continue
+		}
+
+		log.Infof("apply seed: %s", seed.Version)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := seed.apply(tx); err != nil {
+				return err
+			}
+
+			record := &seedVersion{Version: seed.Version, AppliedAt: time.Now()}
+			if applied[seed.Version] {
+				return tx.Save(record).Error
+			}
+			return tx.Create(record).Error
+		})
+		if err != nil {
+			return count, errors2.Wrapf(err, "apply seed %s", seed.Version)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (s *Seed) apply(tx *gorm.DB) error {
+	for _, v := range s.Owners {
+		_, err := upsertOwner(tx, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range s.Pets {
+		_, err := upsertPet(tx, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range s.Ownerships {
+		if err := ensureOwnership(tx, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func upsertOwner(tx *gorm.DB, in *OwnerSeed) (*petmodel.Owner, error) {
+	var r petmodel.Owner
+	err := tx.Where("name = ?", in.Name).Limit(1).Find(&r).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Id == "" {
+		r = petmodel.Owner{Name: in.Name, Age: in.Age, Sex: in.Sex, Phone: in.Phone}
+		return &r, tx.Create(&r).Error
+	}
+
+	if r.Age == in.Age && r.Sex == in.Sex && r.Phone == in.Phone {
+		return &r, nil
+	}
+
+	// map 更新零值，保证与种子一致
+	return &r, tx.Model(&r).Updates(map[string]interface{}{
+		"age":     in.Age,
+		"sex":     in.Sex,
+		"phone":   in.Phone,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+func upsertPet(tx *gorm.DB, in *PetSeed) (*petmodel.Pet, error) {
+	var r petmodel.Pet
+	err := tx.Where("name = ?", in.Name).Limit(1).Find(&r).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Id == "" {
+		r = petmodel.Pet{Name: in.Name, Type: in.Type, Age: in.Age, Sex: in.Sex}
+		return &r, tx.Create(&r).Error
+	}
+
+	if r.Type == in.Type && r.Age == in.Age && r.Sex == in.Sex {
+		return &r, nil
+	}
+
+	return &r, tx.Model(&r).Updates(map[string]interface{}{
+		"type":    in.Type,
+		"age":     in.Age,
+		"sex":     in.Sex,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+func ensureOwnership(tx *gorm.DB, in *Ownership) error {
+	var owner petmodel.Owner
+	err := tx.Where("name = ?", in.Owner).First(&owner).Error
+	if err != nil {
+		return errors2.Wrapf(err, "owner: %s", in.Owner)
+	}
+
+	var pet petmodel.Pet
+	err = tx.Where("name = ?", in.Pet).First(&pet).Error
+	if err != nil {
+		return errors2.Wrapf(err, "pet: %s", in.Pet)
+	}
+
+	var count int64
+	err = tx.Model(&petmodel.OwnerPet{}).Where("owner_id = ? AND pet_id = ?", owner.Id, pet.Id).Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	err = tx.Create(&petmodel.OwnerPet{OwnerId: owner.Id, PetId: pet.Id}).Error
+	if err != nil {
+		return err
+	}
+
+	// 与 service 一致，有 owner 的 pet 标记为 owned
+	if pet.Owned {
+		return nil
+	}
+	return tx.Model(&pet).Updates(map[string]interface{}{
+		"owned":   true,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+// 获取锁后从目录加载并执行种子
+func ApplySeedDir(ctx context.Context, db *dbcore.DB, dir string, force bool) (int, error) {
+	seeds, err := LoadSeeds(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = withLock(ctx, db, func() error {
+		count, err = ApplySeeds(db.Get(ctx), seeds, force)
+		return err
+	})
+	return count, err
+}
@@ -0,0 +1,119 @@
+package dbinit
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+	_ "github.com/win5do/golang-microservice-demo/pkg/repository/db/pet"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestLoadSeeds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile(t, dir, "b.yaml", `
+pets:
+  - name: mimi
+    type: cat
+`)
+	writeFile(t, dir, "a.json", `{"version": "c", "owners": [{"name": "gugu"}]}`)
+	writeFile(t, dir, "README.md", "ignored")
+
+	seeds, err := LoadSeeds(dir)
+	require.NoError(t, err)
+	require.Len(t, seeds, 2)
+	require.Equal(t, "b", seeds[0].Version)
+	require.Equal(t, "cat", seeds[0].Pets[0].Type)
+	require.Equal(t, "c", seeds[1].Version)
+
+	// 未知字段
+	writeFile(t, dir, "d.yaml", "pet: []")
+	_, err = LoadSeeds(dir)
+	require.Error(t, err)
+	require.NoError(t, os.Remove(filepath.Join(dir, "d.yaml")))
+
+	writeFile(t, dir, "d.json", `{"owners": [{"name": "gugu", "mobile": "123"}]}`)
+	_, err = LoadSeeds(dir)
+	require.Error(t, err)
+	require.NoError(t, os.Remove(filepath.Join(dir, "d.json")))
+
+	writeFile(t, dir, "d.yaml", "version: b")
+	_, err = LoadSeeds(dir)
+	require.Error(t, err)
+}
+
+func TestApplySeeds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cdb, err := dbcore.Connect(context.Background(), &dbcore.DBConfig{
+		Driver: dbcore.DriverSqlite,
+		DSN:    filepath.Join(dir, "test.db"),
+	})
+	require.NoError(t, err)
+	defer cdb.Close()
+
+	ctx := context.Background()
+	_, err = migration.Up(ctx, cdb, 0)
+	require.NoError(t, err)
+	db := cdb.Get(ctx)
+
+	seed := &Seed{
+		Version: "1",
+		Owners:  []*OwnerSeed{{Name: "gugu", Age: 30}},
+		Pets:    []*PetSeed{{Name: "mimi", Type: "cat"}, {Name: "wangcai", Type: "dog"}},
+		Ownerships: []*Ownership{
+			{Owner: "gugu", Pet: "mimi"},
+		},
+	}
+
+	n, err := ApplySeeds(db, []*Seed{seed}, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// 已执行的版本跳过
+	n, err = ApplySeeds(db, []*Seed{seed}, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	// 重新执行不会产生重复数据，修改的字段更新为种子中的值
+	seed.Pets[1].Type = "cat"
+	n, err = ApplySeeds(db, []*Seed{seed}, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	var pets []*petmodel.Pet
+	require.NoError(t, db.Order("name").Find(&pets).Error)
+	require.Len(t, pets, 2)
+	require.True(t, pets[0].Owned)
+	require.Equal(t, "cat", pets[1].Type)
+	require.False(t, pets[1].Owned)
+
+	var count int64
+	require.NoError(t, db.Model(&petmodel.OwnerPet{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	// 引用不存在的记录时回滚
+	_, err = ApplySeeds(db, []*Seed{{
+		Version:    "2",
+		Pets:       []*PetSeed{{Name: "xiaohua"}},
+		Ownerships: []*Ownership{{Owner: "dudu", Pet: "xiaohua"}},
+	}}, false)
+	require.Error(t, err)
+	require.NoError(t, db.Model(&petmodel.Pet{}).Where("name = ?", "xiaohua").Count(&count).Error)
+	require.Equal(t, int64(0), count)
+}
@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+
+	errors2 "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	jobmodel "github.com/win5do/golang-microservice-demo/pkg/model/job"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+type jobRunDb struct {
+	db *dbcore.DB
+}
+
+func NewJobRunDb(db *dbcore.DB) *jobRunDb {
+	return &jobRunDb{db: db}
+}
+
+func (s *jobRunDb) Create(ctx context.Context, in *jobmodel.JobRun) (*jobmodel.JobRun, error) {
+	err := s.db.Get(ctx).Create(in).Error
+	if err != nil {
+		if in.ScheduledAt != nil && dbcore.IsUniqueViolation(err) {
+			return nil, errors2.Wrapf(errcode.Err_conflict, "job %s already run at %s", in.Job, in.ScheduledAt)
+		}
+		return nil, errx.WithStackOnce(err)
+	}
+
+	return in, nil
+}
+
+func (s *jobRunDb) Finish(ctx context.Context, in *jobmodel.JobRun) error {
+	err := s.db.Get(ctx).Model(&jobmodel.JobRun{}).
+		Where("id = ?", in.Id).
+		Updates(map[string]interface{}{
+			"ended_at": in.EndedAt,
+			"status":   in.Status,
+			"error":    in.Error,
+		}).
+		Error
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
+
+	return nil
+}
+
+func (s *jobRunDb) Last(ctx context.Context, jobs ...string) (map[string]*jobmodel.JobRun, error) {
+	r := make(map[string]*jobmodel.JobRun, len(jobs))
+	for _, v := range jobs {
+		var run jobmodel.JobRun
+		err := s.db.Get(ctx).Where("job = ?", v).Order("started_at desc").First(&run).Error
+		if err != nil {
+			if errors2.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, errx.WithStackOnce(err)
+		}
+		r[v] = &run
+	}
+
+	return r, nil
+}
+
+func (s *jobRunDb) List(ctx context.Context, job string, limit int) ([]*jobmodel.JobRun, error) {
+	var r []*jobmodel.JobRun
+	db := dbcore.WithOffsetLimit(s.db.Get(ctx), 0, limit)
+	err := db.Where("job = ?", job).Order("started_at desc").Find(&r).Error
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	return r, nil
+}
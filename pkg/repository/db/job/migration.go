@@ -0,0 +1,74 @@
+package job
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+)
+
+// 迁移中使用表结构的快照，不引用 model
+type jobRunV1 struct {
+	Id        string    `gorm:"primarykey"`
+	Job       string    `gorm:"index:idx_job_run_job_started;size:191;not null"`
+	StartedAt time.Time `gorm:"index:idx_job_run_job_started"`
+	EndedAt   *time.Time
+	Status    string `gorm:"size:16;not null"`
+	Error     string `gorm:"type:text"`
+	Holder    string
+	Manual    bool
+}
+
+func (jobRunV1) TableName() string { return "tb_job_runs" }
+
+// 增加调度时间点，与 job 组成唯一索引
+type jobRunV2 struct {
+	Job         string     `gorm:"uniqueIndex:idx_job_run_job_scheduled;size:191;not null"`
+	ScheduledAt *time.Time `gorm:"uniqueIndex:idx_job_run_job_scheduled"`
+}
+
+func (jobRunV2) TableName() string { return "tb_job_runs" }
+
+func init() {
+	migration.Register(&migration.Migration{
+		Version:  2021010201,
+		Name:     "create job run table",
+		Checksum: "1",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&jobRunV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&jobRunV1{})
+		},
+	}, &migration.Migration{
+		Version:  2021010301,
+		Name:     "add job run scheduled at",
+		Checksum: "1",
+		// 不使用 AutoMigrate，sqlite 修改列时重建表会丢失其他索引
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&jobRunV2{}, "ScheduledAt")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&jobRunV2{}, "idx_job_run_job_scheduled")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropIndex(&jobRunV2{}, "idx_job_run_job_scheduled")
+			if err != nil {
+				return err
+			}
+
+			err = tx.Migrator().DropColumn(&jobRunV2{}, "scheduled_at")
+			if err != nil {
+				return err
+			}
+
+			// sqlite 删除列时重建表，需要重新创建索引
+			if !tx.Migrator().HasIndex(&jobRunV1{}, "idx_job_run_job_started") {
+				return tx.Migrator().CreateIndex(&jobRunV1{}, "idx_job_run_job_started")
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+// 迁移中使用表结构的快照，不引用 dbcore
+type lockHolderV1 struct {
+	Id        string `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ExpiredAt time.Time
+	Action    string `gorm:"uniqueIndex:idx_lock_action_holder;size:191;not null"`
+	Holder    string `gorm:"uniqueIndex:idx_lock_action_holder;size:191;not null"`
+	Mode      string `gorm:"size:16;not null"`
+	Holds     int    `gorm:"not null"`
+	Token     int64
+}
+
+func (lockHolderV1) TableName() string { return "tb_lock_holders" }
+
+type lockTokenV1 struct {
+	Action string `gorm:"primarykey"`
+	Token  int64
+}
+
+func (lockTokenV1) TableName() string { return "tb_lock_tokens" }
+
+// 迁移在分布式锁中执行，锁表由 bootstrap 在加锁前创建
+//
+// 旧版本使用的 tb_locks 在这里保留，滚动升级时旧副本仍在用它加锁，删除后旧副本会因表不存在而失败
+// 等所有副本都升级到使用新锁表的版本后，再在之后发布的版本中增加迁移删除 tb_locks，不能与本迁移在同一个版本中发布
+var lockTables = &Migration{
+	Version:  2021010100,
+	Name:     "create lock tables",
+	Checksum: "1",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&lockHolderV1{}, &lockTokenV1{})
+	},
+}
+
+func init() {
+	Register(lockTables)
+}
+
+// 创建迁移记录表和锁表，此时还无法加锁，多个副本可能同时执行，返回是否执行了创建锁表的迁移
+// 并发建表失败时重试，AutoMigrate 发现表已存在后不再创建，记录已被其他副本写入时忽略
+func bootstrap(db *gorm.DB) (bool, error) {
+	var err error
+	for i := 1; i <= 3; i++ {
+		if i > 1 {
+			time.Sleep(time.Duration(i-1) * 100 * time.Millisecond)
+		}
+
+		// 记录表增加的列也在这里添加
+		err = db.AutoMigrate(&schemaMigration{})
+		if err != nil {
+			continue
+		}
+
+		var count int64
+		err = db.Model(&schemaMigration{}).Where("version = ?", lockTables.Version).Count(&count).Error
+		if err != nil {
+			return false, errx.WithStackOnce(err)
+		}
+
+		if count > 0 {
+			return false, nil
+		}
+
+		log.Infof("migrate up: %d %s", lockTables.Version, lockTables.Name)
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := lockTables.up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&schemaMigration{
+				Version:   lockTables.Version,
+				Name:      lockTables.Name,
+				Checksum:  lockTables.checksum(),
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err == nil {
+			return true, nil
+		}
+
+		if dbcore.IsUniqueViolation(err) {
+			return false, nil
+		}
+	}
+
+	return false, errx.WithStackOnce(err)
+}
@@ -0,0 +1,324 @@
+// Package migration 版本化的数据库迁移
+//
+// 各模块在 init 中注册迁移，按版本号顺序执行，执行记录保存在 tb_schema_migrations 表。
+// 多副本通过 dbcore 分布式锁保证同一时间只有一个在执行。
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+const lockAction = "migrate"
+
+// 迁移以 Go 函数或 SQL 定义，Go 函数可以跨数据库驱动，也可以在其中回填数据
+// 每个迁移在独立事务中执行，注意 mysql 的 DDL 会隐式提交，不能回滚
+type Migration struct {
+	Version int64 // 全局唯一，按升序执行，建议使用日期，如 2021010201
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // 为空表示不可回滚
+
+	// 按顺序执行的 SQL 语句，设置后忽略 Up/Down
+	UpSQL   []string
+	DownSQL []string
+
+	// 执行时记录，之后不一致时拒绝迁移，为空时使用 UpSQL 的 sha256
+	// 无法对 Go 函数计算摘要，使用 Up 时必须设置，修改已发布的 Up 时需要同时修改，如递增
+	Checksum string
+}
+
+// 迁移执行记录
+type schemaMigration struct {
+	Version   int64 `gorm:"primarykey;autoIncrement:false"`
+	Name      string
+	Checksum  string `gorm:"size:64"`
+	AppliedAt time.Time
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var migrations = map[int64]*Migration{}
+
+func Register(ms ...*Migration) {
+	for _, m := range ms {
+		if _, ok := migrations[m.Version]; ok {
+			log.Panicf("duplicate migration version: %d", m.Version)
+		}
+		if m.Up == nil && len(m.UpSQL) == 0 {
+			log.Panicf("migration %d: up is nil", m.Version)
+		}
+		if len(m.UpSQL) == 0 && m.Checksum == "" {
+			log.Panicf("migration %d: checksum is required for go migrations", m.Version)
+		}
+		migrations[m.Version] = m
+	}
+}
+
+func (m *Migration) checksum() string {
+	if m.Checksum != "" || len(m.UpSQL) == 0 {
+		return m.Checksum
+	}
+
+	h := sha256.New()
+	for _, v := range m.UpSQL {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (m *Migration) up(tx *gorm.DB) error {
+	if len(m.UpSQL) > 0 {
+		return execAll(tx, m.UpSQL)
+	}
+	return m.Up(tx)
+}
+
+func (m *Migration) down(tx *gorm.DB) error {
+	if len(m.DownSQL) > 0 {
+		return execAll(tx, m.DownSQL)
+	}
+	return m.Down(tx)
+}
+
+func (m *Migration) reversible() bool {
+	if len(m.UpSQL) > 0 {
+		return len(m.DownSQL) > 0
+	}
+	return m.Down != nil
+}
+
+func execAll(tx *gorm.DB, stmts []string) error {
+	for _, v := range stmts {
+		if err := tx.Exec(v).Error; err != nil {
+			return errx.WithStackOnce(err)
+		}
+	}
+	return nil
+}
+
+// 按版本号升序
+func sorted() []*Migration {
+	r := make([]*Migration, 0, len(migrations))
+	for _, v := range migrations {
+		r = append(r, v)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Version < r[j].Version
+	})
+	return r
+}
+
+// 记录表由 bootstrap 创建，不存在时表示没有执行过迁移
+func applied(db *gorm.DB) (map[int64]*schemaMigration, error) {
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return map[int64]*schemaMigration{}, nil
+	}
+
+	var records []*schemaMigration
+	err := db.Order("version").Find(&records).Error
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	r := make(map[int64]*schemaMigration, len(records))
+	for _, v := range records {
+		r[v.Version] = v
+	}
+	return r, nil
+}
+
+func Statuses(ctx context.Context, db *dbcore.DB) ([]*Status, error) {
+	done, err := applied(db.Get(dbcore.WithPrimary(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	var r []*Status
+	for _, v := range sorted() {
+		s := &Status{Version: v.Version, Name: v.Name}
+		if record, ok := done[v.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = record.AppliedAt
+		}
+		r = append(r, s)
+	}
+	return r, nil
+}
+
+// 就绪检查，存在未执行的迁移或已执行的迁移被修改时返回错误
+func Check(db *dbcore.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done, err := applied(db.Get(dbcore.WithPrimary(ctx)))
+		if err != nil {
+			return err
+		}
+
+		if err := verify(done); err != nil {
+			return err
+		}
+
+		var pending []int64
+		for _, v := range sorted() {
+			if _, ok := done[v.Version]; !ok {
+				pending = append(pending, v.Version)
+			}
+		}
+		if len(pending) > 0 {
+			return errors2.Errorf("pending migrations: %v", pending)
+		}
+		return nil
+	}
+}
+
+// 执行到 target 版本为止的所有未执行的迁移，target 为 0 表示全部，返回执行的个数
+func Up(ctx context.Context, cdb *dbcore.DB, target int64) (int, error) {
+	db := cdb.Get(dbcore.WithPrimary(ctx))
+	var count int
+	ok, err := bootstrap(db)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		count++
+	}
+
+	err = withLock(ctx, cdb, func() error {
+		done, err := applied(db)
+		if err != nil {
+			return err
+		}
+
+		if err := verify(done); err != nil {
+			return err
+		}
+
+		for _, v := range sorted() {
+			if target > 0 && v.Version > target {
+				break
+			}
+
+			if _, ok := done[v.Version]; ok {
+				continue
+			}
+
+			log.Infof("migrate up: %d %s", v.Version, v.Name)
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := v.up(tx); err != nil {
+					return err
+				}
+
+				return tx.Create(&schemaMigration{
+					Version:   v.Version,
+					Name:      v.Name,
+					Checksum:  v.checksum(),
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return errors2.Wrapf(err, "migrate up %d %s", v.Version, v.Name)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// 回滚最近执行的 steps 个迁移，返回回滚的个数
+func Down(ctx context.Context, cdb *dbcore.DB, steps int) (int, error) {
+	db := cdb.Get(dbcore.WithPrimary(ctx))
+	if _, err := bootstrap(db); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := withLock(ctx, cdb, func() error {
+		done, err := applied(db)
+		if err != nil {
+			return err
+		}
+
+		if err := verify(done); err != nil {
+			return err
+		}
+
+		ms := sorted()
+		for i := len(ms) - 1; i >= 0 && count < steps; i-- {
+			v := ms[i]
+			if _, ok := done[v.Version]; !ok {
+				continue
+			}
+
+			if !v.reversible() {
+				return errors2.Errorf("migration %d %s is irreversible", v.Version, v.Name)
+			}
+
+			log.Infof("migrate down: %d %s", v.Version, v.Name)
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := v.down(tx); err != nil {
+					return err
+				}
+
+				return tx.Delete(&schemaMigration{}, "version = ?", v.Version).Error
+			})
+			if err != nil {
+				return errors2.Wrapf(err, "migrate down %d %s", v.Version, v.Name)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// 已执行的迁移必须仍然存在且名称和摘要一致，防止修改或删除已发布的迁移
+func verify(done map[int64]*schemaMigration) error {
+	for version, record := range done {
+		m, ok := migrations[version]
+		if !ok {
+			return errors2.Errorf("applied migration %d %s not found", version, record.Name)
+		}
+
+		if m.Name != record.Name {
+			return errors2.Errorf("migration %d changed: applied %q, registered %q", version, record.Name, m.Name)
+		}
+
+		if m.checksum() != record.Checksum {
+			return errors2.Errorf("migration %d %s changed: checksum mismatch", version, m.Name)
+		}
+	}
+	return nil
+}
+
+// 获取分布式锁后执行，锁被其他副本或本机的其他进程持有时等待，需要先执行 bootstrap 创建锁表
+func withLock(ctx context.Context, db *dbcore.DB, fn func() error) error {
+	locker := dbcore.NewLockDb(db, lockAction, dbcore.GetProcessIdentity(), dbcore.DefaultLeaseAge)
+	if err := locker.LockContext(ctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = locker.UnLock()
+	}()
+
+	return fn()
+}
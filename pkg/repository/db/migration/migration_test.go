@@ -0,0 +1,176 @@
+package migration
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+type item struct {
+	Id   string `gorm:"primarykey"`
+	Name string
+}
+
+// 只保留创建锁表的迁移
+func reset() {
+	migrations = map[int64]*Migration{lockTables.Version: lockTables}
+}
+
+func TestMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := dbcore.Connect(context.Background(), &dbcore.DBConfig{
+		Driver: dbcore.DriverSqlite,
+		DSN:    filepath.Join(dir, "test.db"),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	reset()
+	Register(&Migration{
+		Version:  2021010201,
+		Name:     "create items",
+		Checksum: "1",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&item{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&item{})
+		},
+	}, &Migration{
+		Version:  2021010202,
+		Name:     "add item",
+		Checksum: "1",
+		Up: func(tx *gorm.DB) error {
+			return tx.Create(&item{Id: "1", Name: "gugu"}).Error
+		},
+	})
+
+	ctx := context.Background()
+
+	check := Check(db)
+
+	// 包括创建锁表的迁移
+	n, err := Up(ctx, db, 2021010201)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	// 还有未执行的迁移
+	require.Error(t, check(ctx))
+
+	n, err = Up(ctx, db, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// 重复执行不会再次应用
+	n, err = Up(ctx, db, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.NoError(t, check(ctx))
+
+	statuses, err := Statuses(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	require.True(t, statuses[1].Applied)
+	require.True(t, statuses[2].Applied)
+
+	// 不可回滚
+	_, err = Down(ctx, db, 1)
+	require.Error(t, err)
+
+	migrations[2021010202].Name = "renamed"
+	_, err = Up(ctx, db, 0)
+	require.Error(t, err)
+	require.Error(t, check(ctx))
+	migrations[2021010202].Name = "add item"
+
+	migrations[2021010202].Down = func(tx *gorm.DB) error {
+		return tx.Delete(&item{}, "id = ?", "1").Error
+	}
+	n, err = Down(ctx, db, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.False(t, db.Get(ctx).Migrator().HasTable(&item{}))
+}
+
+// Go 函数无法计算摘要，必须设置 Checksum
+func TestRegisterWithoutChecksum(t *testing.T) {
+	reset()
+	require.Panics(t, func() {
+		Register(&Migration{
+			Version: 2021010201,
+			Name:    "add item",
+			Up: func(tx *gorm.DB) error {
+				return tx.Create(&item{Id: "1", Name: "gugu"}).Error
+			},
+		})
+	})
+}
+
+func TestChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := dbcore.Connect(context.Background(), &dbcore.DBConfig{
+		Driver: dbcore.DriverSqlite,
+		DSN:    filepath.Join(dir, "test.db"),
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	reset()
+	Register(&Migration{
+		Version: 2021010201,
+		Name:    "create items",
+		UpSQL:   []string{"CREATE TABLE tb_items (id VARCHAR(32) PRIMARY KEY, name VARCHAR(32))"},
+		DownSQL: []string{"DROP TABLE tb_items"},
+	}, &Migration{
+		Version:  2021010202,
+		Name:     "add item",
+		Checksum: "1",
+		Up: func(tx *gorm.DB) error {
+			return tx.Create(&item{Id: "1", Name: "gugu"}).Error
+		},
+	})
+
+	ctx := context.Background()
+	check := Check(db)
+
+	n, err := Up(ctx, db, 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.NoError(t, check(ctx))
+
+	// 修改已执行的 SQL
+	migrations[2021010201].UpSQL = []string{"CREATE TABLE tb_items (id VARCHAR(32) PRIMARY KEY, name VARCHAR(64))"}
+	_, err = Up(ctx, db, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch")
+	require.Error(t, check(ctx))
+	migrations[2021010201].UpSQL = []string{"CREATE TABLE tb_items (id VARCHAR(32) PRIMARY KEY, name VARCHAR(32))"}
+
+	// 修改 Go 函数时同时修改了 Checksum
+	migrations[2021010202].Checksum = "2"
+	_, err = Down(ctx, db, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch")
+	migrations[2021010202].Checksum = "1"
+	require.NoError(t, check(ctx))
+
+	migrations[2021010202].Down = func(tx *gorm.DB) error {
+		return tx.Delete(&item{}, "id = ?", "1").Error
+	}
+	n, err = Down(ctx, db, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.False(t, db.Get(ctx).Migrator().HasTable(&item{}))
+}
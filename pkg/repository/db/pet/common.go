@@ -2,25 +2,187 @@ package pet
 
 import (
 	"context"
+	"reflect"
+	"time"
 
+	errors2 "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
 	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 )
 
-type petDomain struct{}
+type petDomain struct {
+	db *dbcore.DB
+}
+
+func NewPetDomain(db *dbcore.DB) *petDomain {
+	return &petDomain{db: db}
+}
+
+func (s *petDomain) PetDb(ctx context.Context) petmodel.IPetDb {
+	return &petDb{s.db.Get(ctx)}
+}
 
-func NewPetDomain() *petDomain {
-	return &petDomain{}
+func (s *petDomain) OwnerDb(ctx context.Context) petmodel.IOwnerDb {
+	return &ownerDb{s.db.Get(ctx)}
 }
 
-func (*petDomain) PetDb(ctx context.Context) petmodel.IPetDb {
-	return &petDb{dbcore.GetDB(ctx)}
+func (s *petDomain) OwnerPetDb(ctx context.Context) petmodel.IOwnerPetDb {
+	return &ownerPetDb{s.db.Get(ctx)}
 }
 
-func (*petDomain) OwnerDb(ctx context.Context) petmodel.IOwnerDb {
-	return &ownerDb{dbcore.GetDB(ctx)}
+// 只读字段，出现在 update mask 中时忽略
+// etag 和 deleted_at 是 api 上的输出字段，gateway 生成的 mask 会带上
+var readonlyColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"version":    true,
+	"etag":       true,
+}
+
+// 校验 update mask，返回需要更新的列和值，"*" 表示全部可更新的列
+// mask 为空时只更新非零值字段
+func updateValues(in interface{}, fields []string, columns ...string) (map[string]interface{}, error) {
+	allowed := make(map[string]bool, len(columns))
+	for _, v := range columns {
+		allowed[v] = true
+	}
+
+	r := make(map[string]interface{})
+	if len(fields) == 0 {
+		for _, v := range columns {
+			if val := filter.ValueOf(in, v); !reflect.ValueOf(val).IsZero() {
+				r[v] = val
+			}
+		}
+		return r, nil
+	}
+
+	for _, v := range fields {
+		switch {
+		case v == "*":
+			for _, c := range columns {
+				r[c] = filter.ValueOf(in, c)
+			}
+			return r, nil
+		case readonlyColumns[v]:
+			continue
+		case !allowed[v]:
+			return nil, errors2.Wrapf(errcode.Err_invalid_params, "update mask: unknown field %q", v)
+		}
+
+		r[v] = filter.ValueOf(in, v)
+	}
+
+	return r, nil
+}
+
+// 乐观锁更新，version 不为 0 时校验版本号，每次更新版本号加 1
+func updateWithVersion(db *gorm.DB, model interface{}, id string, version int64, values map[string]interface{}) error {
+	values["version"] = gorm.Expr("version + 1")
+
+	// 已软删除的记录不允许更新
+	tx := db.Model(model).Where("id = ? AND deleted_at IS NULL", id)
+	if version > 0 {
+		tx = tx.Where("version = ?", version)
+	}
+
+	r := tx.Updates(values)
+	if r.Error != nil {
+		return errx.WithStackOnce(r.Error)
+	}
+
+	if r.RowsAffected == 0 {
+		return notFoundOrConflict(db, model, id)
+	}
+
+	return nil
 }
 
-func (*petDomain) OwnerPetDb(ctx context.Context) petmodel.IOwnerPetDb {
-	return &ownerPetDb{dbcore.GetDB(ctx)}
+// 按 id 软删除，version 不为 0 时校验版本号，id 为空时返回 errcode.Err_invalid_params
+func deleteWithVersion(db *gorm.DB, model interface{}, id string, version int64) error {
+	// 只有 version 时会删除所有相同版本号的记录
+	if id == "" {
+		return errors2.Wrap(errcode.Err_invalid_params, "id is required")
+	}
+
+	tx := db.Where("id = ?", id)
+	if version > 0 {
+		tx = tx.Where("version = ?", version)
+	}
+
+	r := tx.Delete(model)
+	if r.Error != nil {
+		return errx.WithStackOnce(r.Error)
+	}
+
+	if r.RowsAffected == 0 && version > 0 {
+		return notFoundOrConflict(db, model, id)
+	}
+
+	return nil
+}
+
+// 没有更新到记录时，区分记录不存在和版本号不一致
+func notFoundOrConflict(db *gorm.DB, model interface{}, id string) error {
+	var count int64
+	err := dbcore.Primary(db).Model(model).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
+
+	if count == 0 {
+		return errx.WithStackOnce(gorm.ErrRecordNotFound)
+	}
+
+	return errors2.Wrapf(errcode.Err_conflict, "version mismatch: %s", id)
+}
+
+// 恢复软删除的记录，version 不为 0 时校验版本号
+func undelete(db *gorm.DB, model interface{}, id string, version int64) error {
+	tx := db.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id)
+	if version > 0 {
+		tx = tx.Where("version = ?", version)
+	}
+
+	r := tx.Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if r.Error != nil {
+		return errx.WithStackOnce(r.Error)
+	}
+
+	if r.RowsAffected == 0 {
+		var count int64
+		err := db.Unscoped().Model(model).Where("id = ?", id).Count(&count).Error
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+
+		if count == 0 {
+			return errx.WithStackOnce(gorm.ErrRecordNotFound)
+		}
+
+		return errors2.Wrapf(errcode.Err_conflict, "not deleted or version mismatch: %s", id)
+	}
+
+	return nil
+}
+
+// 永久删除软删除时间早于 before 的记录
+func purge(db *gorm.DB, model interface{}, before time.Time) (int64, error) {
+	r := db.Unscoped().Where("deleted_at < ?", before).Delete(model)
+	if r.Error != nil {
+		return 0, errx.WithStackOnce(r.Error)
+	}
+
+	return r.RowsAffected, nil
 }
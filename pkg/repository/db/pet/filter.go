@@ -0,0 +1,101 @@
+package pet
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
+)
+
+// 过滤表达式翻译为 sql，字段已在解析时校验过白名单，值全部走占位符
+func filterClause(expr filter.Expr) clause.Expr {
+	switch e := expr.(type) {
+	case *filter.Compare:
+		return clause.Expr{
+			SQL:  "? " + string(e.Op) + " ?",
+			Vars: []interface{}{clause.Column{Name: e.Field}, e.Value},
+		}
+	case *filter.And:
+		return joinClause(" AND ", filterClause(e.Left), filterClause(e.Right))
+	case *filter.Or:
+		return joinClause(" OR ", filterClause(e.Left), filterClause(e.Right))
+	case *filter.Not:
+		c := filterClause(e.Expr)
+		c.SQL = "NOT (" + c.SQL + ")"
+		return c
+	}
+
+	return clause.Expr{SQL: "1 = 1"}
+}
+
+func joinClause(sep string, exprs ...clause.Expr) clause.Expr {
+	r := clause.Expr{SQL: "("}
+	for i, v := range exprs {
+		if i > 0 {
+			r.SQL += sep
+		}
+		r.SQL += v.SQL
+		r.Vars = append(r.Vars, v.Vars...)
+	}
+	r.SQL += ")"
+	return r
+}
+
+func withDeleted(db *gorm.DB, showDeleted bool) *gorm.DB {
+	if showDeleted {
+		return db.Unscoped()
+	}
+
+	return db
+}
+
+func withFilter(db *gorm.DB, expr filter.Expr) *gorm.DB {
+	if expr == nil {
+		return db
+	}
+
+	return db.Where(filterClause(expr))
+}
+
+// 过滤、排序，并从游标之后取一页，多取一条用于判断是否还有下一页，id 作为最后一列排序保证顺序稳定
+func withPage(db *gorm.DB, page *model.Page) *gorm.DB {
+	db = withDeleted(db, page.ShowDeleted)
+	db = withFilter(db, page.Filter)
+
+	if page.Cursor != nil {
+		db = db.Where(cursorClause(page.OrderBy, page.Cursor))
+	}
+
+	for _, v := range page.OrderBy {
+		db = db.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: v.Field},
+			Desc:   v.Desc,
+		})
+	}
+
+	return db.Order("id").Limit(page.Limit())
+}
+
+// (a, b, id) 排在游标之后：
+// a > va OR (a = va AND b > vb) OR (a = va AND b = vb AND id > vid)
+func cursorClause(orders []filter.Order, cursor *model.Cursor) clause.Expr {
+	var ors []clause.Expr
+	var eqs []clause.Expr
+
+	after := func(column string, desc bool, val interface{}) clause.Expr {
+		op := filter.Gt
+		if desc {
+			op = filter.Lt
+		}
+		return filterClause(&filter.Compare{Field: column, Op: op, Value: val})
+	}
+
+	for i, v := range orders {
+		ors = append(ors, joinClause(" AND ", append(eqs, after(v.Field, v.Desc, cursor.Values[i]))...))
+		eqs = append(eqs, filterClause(&filter.Compare{Field: v.Field, Op: filter.Eq, Value: cursor.Values[i]}))
+	}
+	ors = append(ors, joinClause(" AND ", append(eqs, after("id", false, cursor.Id))...))
+
+	return joinClause(" OR ", ors...)
+}
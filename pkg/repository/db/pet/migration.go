@@ -0,0 +1,62 @@
+package pet
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+)
+
+// 迁移中使用表结构的快照，不引用 model，避免 model 修改后影响已发布的迁移
+type commonV1 struct {
+	Id        string `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int64          `gorm:"not null;default:1"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type petV1 struct {
+	Common commonV1 `gorm:"embedded"`
+	Name   string
+	Type   string
+	Age    uint32
+	Sex    string
+	Owned  bool
+}
+
+func (petV1) TableName() string { return "tb_pets" }
+
+type ownerV1 struct {
+	Common commonV1 `gorm:"embedded"`
+	Name   string
+	Age    uint32
+	Sex    string
+	Phone  string
+}
+
+func (ownerV1) TableName() string { return "tb_owners" }
+
+type ownerPetV1 struct {
+	Common  commonV1 `gorm:"embedded"`
+	OwnerId string
+	PetId   string
+}
+
+func (ownerPetV1) TableName() string { return "tb_owner_pets" }
+
+func init() {
+	migration.Register(&migration.Migration{
+		Version:  2021010101,
+		Name:     "create pet tables",
+		Checksum: "1",
+		// 兼容之前由 AutoMigrate 创建的表
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&petV1{}, &ownerV1{}, &ownerPetV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&ownerPetV1{}, &ownerV1{}, &petV1{})
+		},
+	})
+}
@@ -1,20 +1,17 @@
 package pet
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/win5do/go-lib/errx"
 
+	"github.com/win5do/golang-microservice-demo/pkg/model"
 	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 )
 
-func init() {
-	dbcore.RegisterInjector(func(db *gorm.DB) {
-		dbcore.SetupTableModel(db, &petmodel.Owner{})
-	})
-}
-
 type ownerDb struct {
 	db *gorm.DB
 }
@@ -32,6 +29,31 @@ func (s *ownerDb) List(query *petmodel.Owner, offset, limit int) ([]*petmodel.Ow
 	return r, nil
 }
 
+func (s *ownerDb) Page(query *petmodel.Owner, page *model.Page) ([]*petmodel.Owner, error) {
+	var r []*petmodel.Owner
+
+	db := withPage(s.db, page)
+
+	err := db.Where(query).Find(&r).Error
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	return r, nil
+}
+
+// 统计符合过滤条件的记录数，忽略分页和排序
+func (s *ownerDb) Count(query *petmodel.Owner, page *model.Page) (int64, error) {
+	var r int64
+	db := withDeleted(s.db, page.ShowDeleted)
+	err := withFilter(db, page.Filter).Model(&petmodel.Owner{}).Where(query).Count(&r).Error
+	if err != nil {
+		return 0, errx.WithStackOnce(err)
+	}
+
+	return r, nil
+}
+
 func (s *ownerDb) Get(id string) (*petmodel.Owner, error) {
 	var r petmodel.Owner
 	err := s.db.Where("id = ?", id).First(&r).Error
@@ -51,20 +73,39 @@ func (s *ownerDb) Create(in *petmodel.Owner) (*petmodel.Owner, error) {
 	return in, nil
 }
 
-func (s *ownerDb) Update(in *petmodel.Owner) (*petmodel.Owner, error) {
-	err := s.db.Updates(in).Error
+// fields 为空时只更新非零值字段，否则只更新指定的字段，零值也会更新
+// Version 不为 0 时校验版本号，不一致返回 errcode.Err_conflict
+func (s *ownerDb) Update(in *petmodel.Owner, fields ...string) (*petmodel.Owner, error) {
+	values, err := updateValues(in, fields, "name", "age", "sex", "phone")
 	if err != nil {
-		return nil, errx.WithStackOnce(err)
+		return nil, err
 	}
 
-	return in, nil
+	err = updateWithVersion(s.db, &petmodel.Owner{}, in.Id, in.Version, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// 写入后从主库读取，避免从库延迟读到旧数据
+	return (&ownerDb{dbcore.Primary(s.db)}).Get(in.Id)
 }
 
+// 按 Id 删除，Version 不为 0 时校验版本号，不一致返回 errcode.Err_conflict
 func (s *ownerDb) Delete(in *petmodel.Owner) error {
-	err := s.db.Where(in).Delete(&petmodel.Owner{}).Error
+	return deleteWithVersion(s.db, &petmodel.Owner{}, in.Id, in.Version)
+}
+
+// Version 不为 0 时校验版本号，记录未删除时返回 errcode.Err_conflict
+func (s *ownerDb) Undelete(in *petmodel.Owner) (*petmodel.Owner, error) {
+	err := undelete(s.db, &petmodel.Owner{}, in.Id, in.Version)
 	if err != nil {
-		return errx.WithStackOnce(err)
+		return nil, err
 	}
 
-	return nil
+	// 写入后从主库读取，避免从库延迟读到旧数据
+	return (&ownerDb{dbcore.Primary(s.db)}).Get(in.Id)
+}
+
+func (s *ownerDb) Purge(before time.Time) (int64, error) {
+	return purge(s.db, &petmodel.Owner{}, before)
 }
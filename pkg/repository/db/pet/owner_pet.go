@@ -1,20 +1,15 @@
 package pet
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/win5do/go-lib/errx"
 
 	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
-	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 )
 
-func init() {
-	dbcore.RegisterInjector(func(db *gorm.DB) {
-		dbcore.SetupTableModel(db, &petmodel.OwnerPet{})
-	})
-}
-
 type ownerPetDb struct {
 	db *gorm.DB
 }
@@ -46,3 +41,7 @@ func (s *ownerPetDb) Delete(in *petmodel.OwnerPet) error {
 
 	return nil
 }
+
+func (s *ownerPetDb) Purge(before time.Time) (int64, error) {
+	return purge(s.db, &petmodel.OwnerPet{}, before)
+}
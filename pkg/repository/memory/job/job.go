@@ -0,0 +1,105 @@
+package job
+
+import (
+	"context"
+	"sort"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	jobmodel "github.com/win5do/golang-microservice-demo/pkg/model/job"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/memory/memcore"
+)
+
+const tableJobRun = "job_runs"
+
+type jobRunDb struct {
+	store *memcore.Store
+}
+
+func NewJobRunDb() *jobRunDb {
+	return &jobRunDb{store: memcore.NewStore(tableJobRun)}
+}
+
+func (s *jobRunDb) Create(ctx context.Context, in *jobmodel.JobRun) (*jobmodel.JobRun, error) {
+	in.Id = dbcore.NewUlid()
+	err := s.store.Update(ctx, func(tables memcore.Tables) error {
+		t := tables.Table(tableJobRun)
+		if in.ScheduledAt != nil {
+			for _, v := range t {
+				run := v.(jobmodel.JobRun)
+				if run.Job == in.Job && run.ScheduledAt != nil && run.ScheduledAt.Equal(*in.ScheduledAt) {
+					return errors2.Wrapf(errcode.Err_conflict, "job %s already run at %s", in.Job, in.ScheduledAt)
+				}
+			}
+		}
+
+		t[in.Id] = *in
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+func (s *jobRunDb) Finish(ctx context.Context, in *jobmodel.JobRun) error {
+	return s.store.Update(ctx, func(tables memcore.Tables) error {
+		t := tables.Table(tableJobRun)
+		v, ok := t[in.Id]
+		if !ok {
+			return nil
+		}
+
+		run := v.(jobmodel.JobRun)
+		run.EndedAt = in.EndedAt
+		run.Status = in.Status
+		run.Error = in.Error
+		t[in.Id] = run
+		return nil
+	})
+}
+
+func (s *jobRunDb) Last(ctx context.Context, jobs ...string) (map[string]*jobmodel.JobRun, error) {
+	r := make(map[string]*jobmodel.JobRun, len(jobs))
+	for _, v := range jobs {
+		runs, err := s.List(ctx, v, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(runs) > 0 {
+			r[v] = runs[0]
+		}
+	}
+
+	return r, nil
+}
+
+func (s *jobRunDb) List(ctx context.Context, job string, limit int) ([]*jobmodel.JobRun, error) {
+	var r []*jobmodel.JobRun
+	err := s.store.View(ctx, func(tables memcore.Tables) error {
+		for _, v := range tables.Table(tableJobRun) {
+			run := v.(jobmodel.JobRun)
+			if run.Job == job {
+				r = append(r, &run)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].StartedAt.After(r[j].StartedAt)
+	})
+
+	if limit > 0 && len(r) > limit {
+		r = r[:limit]
+	}
+
+	return r, nil
+}
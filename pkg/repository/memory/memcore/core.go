@@ -0,0 +1,118 @@
+// Package memcore 线程安全的内存存储，用于没有数据库的开发和测试环境
+package memcore
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+)
+
+// 一张表，id -> 记录，记录保存 struct 值而不是指针，读写时都会复制
+type Table map[string]interface{}
+
+type Tables map[string]Table
+
+func (s Tables) Table(name string) Table {
+	t, ok := s[name]
+	if !ok {
+		log.Panicf("unknown table: %s", name)
+	}
+	return t
+}
+
+// 复制一份表数据，记录为值类型，复制 map 即可
+func (s Tables) clone() Tables {
+	r := make(Tables, len(s))
+	for name, t := range s {
+		nt := make(Table, len(t))
+		for k, v := range t {
+			nt[k] = v
+		}
+		r[name] = nt
+	}
+	return r
+}
+
+type Store struct {
+	mu     sync.RWMutex
+	tables Tables
+}
+
+func NewStore(tables ...string) *Store {
+	s := &Store{
+		tables: make(Tables, len(tables)),
+	}
+	for _, v := range tables {
+		s.tables[v] = make(Table)
+	}
+	return s
+}
+
+type ctxTransactionKey struct{}
+
+// 事务中的数据副本
+type txData struct {
+	tables Tables
+}
+
+func getTx(ctx context.Context) *txData {
+	if tx, ok := ctx.Value(ctxTransactionKey{}).(*txData); ok {
+		return tx
+	}
+	return nil
+}
+
+// 只读访问
+func (s *Store) View(ctx context.Context, fn func(tables Tables) error) error {
+	if tx := getTx(ctx); tx != nil {
+		// 事务持有写锁
+		return fn(tx.tables)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.tables)
+}
+
+// 读写访问，fn 返回错误时不会回滚已做的修改，需要先校验再修改
+func (s *Store) Update(ctx context.Context, fn func(tables Tables) error) error {
+	if tx := getTx(ctx); tx != nil {
+		return fn(tx.tables)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.tables)
+}
+
+// 实现 model.ITransaction
+//
+// 事务期间持有写锁，在数据副本上执行 fn，成功后替换，失败则丢弃副本实现回滚。
+// 嵌套调用时在外层副本上再复制一份，相当于 savepoint。
+// 事务中只能使用 txctx 访问 Store，否则会死锁。隔离级别和只读选项不生效。
+func (s *Store) Transaction(ctx context.Context, fn func(txctx context.Context) error, opts ...model.TxOption) error {
+	ctx, cancel := model.NewTxOptions(opts...).Context(ctx)
+	defer cancel()
+
+	if parent := getTx(ctx); parent != nil {
+		tx := &txData{tables: parent.tables.clone()}
+		if err := fn(context.WithValue(ctx, ctxTransactionKey{}, tx)); err != nil {
+			return err
+		}
+		parent.tables = tx.tables
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &txData{tables: s.tables.clone()}
+	if err := fn(context.WithValue(ctx, ctxTransactionKey{}, tx)); err != nil {
+		return err
+	}
+	s.tables = tx.tables
+	return nil
+}
@@ -0,0 +1,269 @@
+package pet
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/memory/memcore"
+)
+
+const (
+	tablePet      = "pets"
+	tableOwner    = "owners"
+	tableOwnerPet = "owner_pets"
+)
+
+// 新建存储，同时实现 model.ITransaction
+func NewStore() *memcore.Store {
+	return memcore.NewStore(tablePet, tableOwner, tableOwnerPet)
+}
+
+type petDomain struct {
+	store *memcore.Store
+}
+
+func NewPetDomain(store *memcore.Store) *petDomain {
+	return &petDomain{store: store}
+}
+
+func (s *petDomain) PetDb(ctx context.Context) petmodel.IPetDb {
+	return &petDb{ctx: ctx, store: s.store}
+}
+
+func (s *petDomain) OwnerDb(ctx context.Context) petmodel.IOwnerDb {
+	return &ownerDb{ctx: ctx, store: s.store}
+}
+
+func (s *petDomain) OwnerPetDb(ctx context.Context) petmodel.IOwnerPetDb {
+	return &ownerPetDb{ctx: ctx, store: s.store}
+}
+
+func newCommon() model.Common {
+	now := time.Now()
+	return model.Common{
+		Id:        dbcore.NewUlid(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+}
+
+func notFound(id string) error {
+	return errors2.Wrapf(errcode.Err_not_found, "id: %s", id)
+}
+
+// 取出未删除的记录用于更新，version 不为 0 时校验版本号
+func checkVersion(c *model.Common, version int64) error {
+	if c.DeletedAt.Valid {
+		return notFound(c.Id)
+	}
+
+	if version > 0 && c.Version != version {
+		return errors2.Wrapf(errcode.Err_conflict, "version mismatch: %s", c.Id)
+	}
+
+	return nil
+}
+
+func touch(c *model.Common) {
+	c.UpdatedAt = time.Now()
+	c.Version++
+}
+
+func softDelete(c *model.Common) {
+	c.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
+
+// 恢复软删除，version 不为 0 时校验版本号
+func undelete(c *model.Common, version int64) error {
+	if !c.DeletedAt.Valid || (version > 0 && c.Version != version) {
+		return errors2.Wrapf(errcode.Err_conflict, "not deleted or version mismatch: %s", c.Id)
+	}
+
+	c.DeletedAt = gorm.DeletedAt{}
+	touch(c)
+	return nil
+}
+
+func purgeable(c *model.Common, before time.Time) bool {
+	return c.DeletedAt.Valid && c.DeletedAt.Time.Before(before)
+}
+
+// 与 gorm 的 Where(struct) 一致，只比较非零值字段
+func matchQuery(query, item interface{}, columns []string) bool {
+	for _, v := range columns {
+		q := filter.ValueOf(query, v)
+		if q == nil || isZero(q) {
+			continue
+		}
+
+		if c, ok := filter.CompareValues(filter.ValueOf(item, v), q); !ok || c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 是否有可用作查询条件的非零值字段
+func hasQuery(query interface{}, columns []string) bool {
+	for _, v := range columns {
+		if q := filter.ValueOf(query, v); q != nil && !isZero(q) {
+			return true
+		}
+	}
+	return false
+}
+
+func isZero(v interface{}) bool {
+	switch x := v.(type) {
+	case string:
+		return x == ""
+	case int64:
+		return x == 0
+	case bool:
+		return !x
+	case time.Time:
+		return x.IsZero()
+	}
+	return false
+}
+
+// items 为同一类型的记录指针
+func sortById(items []interface{}) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].(model.Object).GetId() < items[j].(model.Object).GetId()
+	})
+}
+
+func offsetLimit(items []interface{}, offset, limit int) []interface{} {
+	if offset > 0 {
+		if offset >= len(items) {
+			return nil
+		}
+		items = items[offset:]
+	}
+
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// 过滤、排序，并从游标之后取一页，与 db 实现一致，多取一条，id 作为最后一列排序
+func pageItems(items []interface{}, page *model.Page) []interface{} {
+	var r []interface{}
+	for _, v := range items {
+		if !filter.Match(page.Filter, v) {
+			continue
+		}
+
+		if page.Cursor != nil && compareCursor(v, page) <= 0 {
+			continue
+		}
+
+		r = append(r, v)
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return compareItems(r[i], r[j], page.OrderBy) < 0
+	})
+
+	return offsetLimit(r, 0, page.Limit())
+}
+
+func filterItems(items []interface{}, expr filter.Expr) []interface{} {
+	var r []interface{}
+	for _, v := range items {
+		if filter.Match(expr, v) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// 按排序方向比较两条记录
+func compareItems(a, b interface{}, orders []filter.Order) int {
+	for _, v := range orders {
+		c, _ := filter.CompareValues(filter.ValueOf(a, v.Field), filter.ValueOf(b, v.Field))
+		if c != 0 {
+			if v.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+
+	c, _ := filter.CompareValues(a.(model.Object).GetId(), b.(model.Object).GetId())
+	return c
+}
+
+// 记录在游标之后返回正数
+func compareCursor(item interface{}, page *model.Page) int {
+	for i, v := range page.OrderBy {
+		c, _ := filter.CompareValues(filter.ValueOf(item, v.Field), page.Cursor.Values[i])
+		if c != 0 {
+			if v.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+
+	c, _ := filter.CompareValues(item.(model.Object).GetId(), page.Cursor.Id)
+	return c
+}
+
+// 只读字段，出现在 update mask 中时忽略
+// etag 和 deleted_at 是 api 上的输出字段，gateway 生成的 mask 会带上
+var readonlyColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"version":    true,
+	"etag":       true,
+}
+
+// 校验 update mask，返回需要更新的列，"*" 表示全部可更新的列
+// mask 为空时只更新非零值字段
+func updateColumns(in interface{}, fields []string, columns ...string) ([]string, error) {
+	allowed := make(map[string]bool, len(columns))
+	for _, v := range columns {
+		allowed[v] = true
+	}
+
+	var r []string
+	if len(fields) == 0 {
+		for _, v := range columns {
+			if !isZero(filter.ValueOf(in, v)) {
+				r = append(r, v)
+			}
+		}
+		return r, nil
+	}
+
+	for _, v := range fields {
+		switch {
+		case v == "*":
+			return columns, nil
+		case readonlyColumns[v]:
+			continue
+		case !allowed[v]:
+			return nil, errors2.Wrapf(errcode.Err_invalid_params, "update mask: unknown field %q", v)
+		}
+
+		r = append(r, v)
+	}
+
+	return r, nil
+}
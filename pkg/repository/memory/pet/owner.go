@@ -0,0 +1,223 @@
+package pet
+
+import (
+	"context"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/memory/memcore"
+)
+
+// 可作为查询条件的列
+var ownerColumns = []string{"id", "created_at", "updated_at", "version", "name", "age", "sex", "phone"}
+
+type ownerDb struct {
+	ctx   context.Context
+	store *memcore.Store
+}
+
+// 返回匹配 query 的记录副本，按 id 排序
+func (s *ownerDb) find(t memcore.Tables, query *petmodel.Owner, showDeleted bool) []interface{} {
+	var r []interface{}
+	for _, v := range t.Table(tableOwner) {
+		owner := v.(petmodel.Owner)
+		if owner.DeletedAt.Valid && !showDeleted {
+			continue
+		}
+
+		if !matchQuery(query, &owner, ownerColumns) {
+			continue
+		}
+
+		r = append(r, &owner)
+	}
+	sortById(r)
+	return r
+}
+
+func toOwners(items []interface{}) []*petmodel.Owner {
+	r := make([]*petmodel.Owner, 0, len(items))
+	for _, v := range items {
+		r = append(r, v.(*petmodel.Owner))
+	}
+	return r
+}
+
+func (s *ownerDb) List(query *petmodel.Owner, offset, limit int) ([]*petmodel.Owner, error) {
+	var r []*petmodel.Owner
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = toOwners(offsetLimit(s.find(t, query, false), offset, limit))
+		return nil
+	})
+	return r, err
+}
+
+func (s *ownerDb) Page(query *petmodel.Owner, page *model.Page) ([]*petmodel.Owner, error) {
+	var r []*petmodel.Owner
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = toOwners(pageItems(s.find(t, query, page.ShowDeleted), page))
+		return nil
+	})
+	return r, err
+}
+
+// 统计符合过滤条件的记录数，忽略分页和排序
+func (s *ownerDb) Count(query *petmodel.Owner, page *model.Page) (int64, error) {
+	var r int64
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = int64(len(filterItems(s.find(t, query, page.ShowDeleted), page.Filter)))
+		return nil
+	})
+	return r, err
+}
+
+func (s *ownerDb) Get(id string) (*petmodel.Owner, error) {
+	var r *petmodel.Owner
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		v, ok := t.Table(tableOwner)[id]
+		if !ok {
+			return notFound(id)
+		}
+
+		owner := v.(petmodel.Owner)
+		if owner.DeletedAt.Valid {
+			return notFound(id)
+		}
+
+		r = &owner
+		return nil
+	})
+	return r, err
+}
+
+func (s *ownerDb) Create(in *petmodel.Owner) (*petmodel.Owner, error) {
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		in.Common = newCommon()
+		t.Table(tableOwner)[in.Id] = *in
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+// fields 为空时只更新非零值字段，否则只更新指定的字段，零值也会更新
+// Version 不为 0 时校验版本号，不一致返回 errcode.Err_conflict
+func (s *ownerDb) Update(in *petmodel.Owner, fields ...string) (*petmodel.Owner, error) {
+	columns, err := updateColumns(in, fields, "name", "age", "sex", "phone")
+	if err != nil {
+		return nil, err
+	}
+
+	var r petmodel.Owner
+	err = s.store.Update(s.ctx, func(t memcore.Tables) error {
+		v, ok := t.Table(tableOwner)[in.Id]
+		if !ok {
+			return notFound(in.Id)
+		}
+
+		r = v.(petmodel.Owner)
+		if err := checkVersion(&r.Common, in.Version); err != nil {
+			return err
+		}
+
+		for _, c := range columns {
+			switch c {
+			case "name":
+				r.Name = in.Name
+			case "age":
+				r.Age = in.Age
+			case "sex":
+				r.Sex = in.Sex
+			case "phone":
+				r.Phone = in.Phone
+			}
+		}
+		touch(&r.Common)
+
+		t.Table(tableOwner)[r.Id] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// 按 Id 删除，Version 不为 0 时校验版本号，不一致返回 errcode.Err_conflict
+func (s *ownerDb) Delete(in *petmodel.Owner) error {
+	if in.Id == "" {
+		return errors2.Wrap(errcode.Err_invalid_params, "id is required")
+	}
+
+	return s.store.Update(s.ctx, func(t memcore.Tables) error {
+		// 与 gorm 一致，不校验版本号时删除不存在或已删除的记录不报错
+		v, ok := t.Table(tableOwner)[in.Id]
+		if !ok {
+			if in.Version > 0 {
+				return notFound(in.Id)
+			}
+			return nil
+		}
+
+		owner := v.(petmodel.Owner)
+		if owner.DeletedAt.Valid && in.Version == 0 {
+			return nil
+		}
+
+		if err := checkVersion(&owner.Common, in.Version); err != nil {
+			return err
+		}
+
+		softDelete(&owner.Common)
+		t.Table(tableOwner)[owner.Id] = owner
+		return nil
+	})
+}
+
+// Version 不为 0 时校验版本号，记录未删除时返回 errcode.Err_conflict
+func (s *ownerDb) Undelete(in *petmodel.Owner) (*petmodel.Owner, error) {
+	var r petmodel.Owner
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		v, ok := t.Table(tableOwner)[in.Id]
+		if !ok {
+			return notFound(in.Id)
+		}
+
+		r = v.(petmodel.Owner)
+		if err := undelete(&r.Common, in.Version); err != nil {
+			return err
+		}
+
+		t.Table(tableOwner)[r.Id] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (s *ownerDb) Purge(before time.Time) (int64, error) {
+	var r int64
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		table := t.Table(tableOwner)
+		for id, v := range table {
+			owner := v.(petmodel.Owner)
+			if purgeable(&owner.Common, before) {
+				delete(table, id)
+				r++
+			}
+		}
+		return nil
+	})
+	return r, err
+}
@@ -0,0 +1,91 @@
+package pet
+
+import (
+	"context"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/memory/memcore"
+)
+
+var ownerPetColumns = []string{"id", "created_at", "updated_at", "version", "owner_id", "pet_id"}
+
+type ownerPetDb struct {
+	ctx   context.Context
+	store *memcore.Store
+}
+
+func (s *ownerPetDb) find(t memcore.Tables, query *petmodel.OwnerPet) []*petmodel.OwnerPet {
+	var items []interface{}
+	for _, v := range t.Table(tableOwnerPet) {
+		op := v.(petmodel.OwnerPet)
+		if op.DeletedAt.Valid || !matchQuery(query, &op, ownerPetColumns) {
+			continue
+		}
+
+		items = append(items, &op)
+	}
+	sortById(items)
+
+	r := make([]*petmodel.OwnerPet, 0, len(items))
+	for _, v := range items {
+		r = append(r, v.(*petmodel.OwnerPet))
+	}
+	return r
+}
+
+func (s *ownerPetDb) Query(in *petmodel.OwnerPet) ([]*petmodel.OwnerPet, error) {
+	var r []*petmodel.OwnerPet
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = s.find(t, in)
+		return nil
+	})
+	return r, err
+}
+
+func (s *ownerPetDb) Create(in *petmodel.OwnerPet) (*petmodel.OwnerPet, error) {
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		in.Common = newCommon()
+		t.Table(tableOwnerPet)[in.Id] = *in
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+func (s *ownerPetDb) Delete(in *petmodel.OwnerPet) error {
+	// 与 gorm 一致，不允许无条件删除
+	if !hasQuery(in, ownerPetColumns) {
+		return errors2.Wrap(errcode.Err_invalid_params, "delete without condition")
+	}
+
+	return s.store.Update(s.ctx, func(t memcore.Tables) error {
+		for _, v := range s.find(t, in) {
+			softDelete(&v.Common)
+			t.Table(tableOwnerPet)[v.Id] = *v
+		}
+		return nil
+	})
+}
+
+func (s *ownerPetDb) Purge(before time.Time) (int64, error) {
+	var r int64
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		table := t.Table(tableOwnerPet)
+		for id, v := range table {
+			op := v.(petmodel.OwnerPet)
+			if purgeable(&op.Common, before) {
+				delete(table, id)
+				r++
+			}
+		}
+		return nil
+	})
+	return r, err
+}
@@ -0,0 +1,225 @@
+package pet
+
+import (
+	"context"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/memory/memcore"
+)
+
+// 可作为查询条件的列
+var petColumns = []string{"id", "created_at", "updated_at", "version", "name", "type", "age", "sex", "owned"}
+
+type petDb struct {
+	ctx   context.Context
+	store *memcore.Store
+}
+
+// 返回匹配 query 的记录副本，按 id 排序
+func (s *petDb) find(t memcore.Tables, query *petmodel.Pet, showDeleted bool) []interface{} {
+	var r []interface{}
+	for _, v := range t.Table(tablePet) {
+		pet := v.(petmodel.Pet)
+		if pet.DeletedAt.Valid && !showDeleted {
+			continue
+		}
+
+		if !matchQuery(query, &pet, petColumns) {
+			continue
+		}
+
+		r = append(r, &pet)
+	}
+	sortById(r)
+	return r
+}
+
+func toPets(items []interface{}) []*petmodel.Pet {
+	r := make([]*petmodel.Pet, 0, len(items))
+	for _, v := range items {
+		r = append(r, v.(*petmodel.Pet))
+	}
+	return r
+}
+
+func (s *petDb) List(query *petmodel.Pet, offset, limit int) ([]*petmodel.Pet, error) {
+	var r []*petmodel.Pet
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = toPets(offsetLimit(s.find(t, query, false), offset, limit))
+		return nil
+	})
+	return r, err
+}
+
+func (s *petDb) Page(query *petmodel.Pet, page *model.Page) ([]*petmodel.Pet, error) {
+	var r []*petmodel.Pet
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = toPets(pageItems(s.find(t, query, page.ShowDeleted), page))
+		return nil
+	})
+	return r, err
+}
+
+// 统计符合过滤条件的记录数，忽略分页和排序
+func (s *petDb) Count(query *petmodel.Pet, page *model.Page) (int64, error) {
+	var r int64
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		r = int64(len(filterItems(s.find(t, query, page.ShowDeleted), page.Filter)))
+		return nil
+	})
+	return r, err
+}
+
+func (s *petDb) Get(id string) (*petmodel.Pet, error) {
+	var r *petmodel.Pet
+	err := s.store.View(s.ctx, func(t memcore.Tables) error {
+		v, ok := t.Table(tablePet)[id]
+		if !ok {
+			return notFound(id)
+		}
+
+		pet := v.(petmodel.Pet)
+		if pet.DeletedAt.Valid {
+			return notFound(id)
+		}
+
+		r = &pet
+		return nil
+	})
+	return r, err
+}
+
+func (s *petDb) Create(in *petmodel.Pet) (*petmodel.Pet, error) {
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		in.Common = newCommon()
+		t.Table(tablePet)[in.Id] = *in
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return in, nil
+}
+
+// fields 为空时只更新非零值字段，否则只更新指定的字段，零值也会更新
+// Version 不为 0 时校验版本号，不一致返回 errcode.Err_conflict
+func (s *petDb) Update(in *petmodel.Pet, fields ...string) (*petmodel.Pet, error) {
+	columns, err := updateColumns(in, fields, "name", "type", "age", "sex", "owned")
+	if err != nil {
+		return nil, err
+	}
+
+	var r petmodel.Pet
+	err = s.store.Update(s.ctx, func(t memcore.Tables) error {
+		v, ok := t.Table(tablePet)[in.Id]
+		if !ok {
+			return notFound(in.Id)
+		}
+
+		r = v.(petmodel.Pet)
+		if err := checkVersion(&r.Common, in.Version); err != nil {
+			return err
+		}
+
+		for _, c := range columns {
+			switch c {
+			case "name":
+				r.Name = in.Name
+			case "type":
+				r.Type = in.Type
+			case "age":
+				r.Age = in.Age
+			case "sex":
+				r.Sex = in.Sex
+			case "owned":
+				r.Owned = in.Owned
+			}
+		}
+		touch(&r.Common)
+
+		t.Table(tablePet)[r.Id] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// 按 Id 删除，Version 不为 0 时校验版本号，不一致返回 errcode.Err_conflict
+func (s *petDb) Delete(in *petmodel.Pet) error {
+	if in.Id == "" {
+		return errors2.Wrap(errcode.Err_invalid_params, "id is required")
+	}
+
+	return s.store.Update(s.ctx, func(t memcore.Tables) error {
+		// 与 gorm 一致，不校验版本号时删除不存在或已删除的记录不报错
+		v, ok := t.Table(tablePet)[in.Id]
+		if !ok {
+			if in.Version > 0 {
+				return notFound(in.Id)
+			}
+			return nil
+		}
+
+		pet := v.(petmodel.Pet)
+		if pet.DeletedAt.Valid && in.Version == 0 {
+			return nil
+		}
+
+		if err := checkVersion(&pet.Common, in.Version); err != nil {
+			return err
+		}
+
+		softDelete(&pet.Common)
+		t.Table(tablePet)[pet.Id] = pet
+		return nil
+	})
+}
+
+// Version 不为 0 时校验版本号，记录未删除时返回 errcode.Err_conflict
+func (s *petDb) Undelete(in *petmodel.Pet) (*petmodel.Pet, error) {
+	var r petmodel.Pet
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		v, ok := t.Table(tablePet)[in.Id]
+		if !ok {
+			return notFound(in.Id)
+		}
+
+		r = v.(petmodel.Pet)
+		if err := undelete(&r.Common, in.Version); err != nil {
+			return err
+		}
+
+		t.Table(tablePet)[r.Id] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (s *petDb) Purge(before time.Time) (int64, error) {
+	var r int64
+	err := s.store.Update(s.ctx, func(t memcore.Tables) error {
+		table := t.Table(tablePet)
+		for id, v := range table {
+			pet := v.(petmodel.Pet)
+			if purgeable(&pet.Common, before) {
+				delete(table, id)
+				r++
+			}
+		}
+		return nil
+	})
+	return r, err
+}
@@ -0,0 +1,151 @@
+package pet_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
+	mempet "github.com/win5do/golang-microservice-demo/pkg/repository/memory/pet"
+)
+
+func TestPetCRUD(t *testing.T) {
+	store := mempet.NewStore()
+	petDb := mempet.NewPetDomain(store).PetDb(context.Background())
+
+	pet, err := petDb.Create(&petmodel.Pet{Name: "gugu", Type: "cat", Age: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, pet.Id)
+	require.Equal(t, int64(1), pet.Version)
+
+	r, err := petDb.Update(&petmodel.Pet{
+		Common: model.Common{Id: pet.Id, Version: 1},
+		Age:    0,
+	}, "age")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), r.Age)
+	require.Equal(t, "gugu", r.Name)
+	require.Equal(t, int64(2), r.Version)
+
+	_, err = petDb.Update(&petmodel.Pet{Common: model.Common{Id: pet.Id, Version: 1}, Name: "qq"})
+	require.True(t, errors.Is(err, errcode.Err_conflict))
+
+	_, err = petDb.Update(&petmodel.Pet{Common: model.Common{Id: pet.Id}}, "color")
+	require.True(t, errors.Is(err, errcode.Err_invalid_params))
+
+	err = petDb.Delete(&petmodel.Pet{Common: model.Common{Id: pet.Id, Version: 1}})
+	require.True(t, errors.Is(err, errcode.Err_conflict))
+
+	// 没有 id 时不能按版本号删除
+	err = petDb.Delete(&petmodel.Pet{Common: model.Common{Version: 2}})
+	require.True(t, errors.Is(err, errcode.Err_invalid_params))
+
+	err = petDb.Delete(&petmodel.Pet{Common: model.Common{Id: pet.Id, Version: 2}})
+	require.NoError(t, err)
+
+	_, err = petDb.Get(pet.Id)
+	require.True(t, errors.Is(err, errcode.Err_not_found))
+
+	r, err = petDb.Undelete(&petmodel.Pet{Common: model.Common{Id: pet.Id}})
+	require.NoError(t, err)
+	require.False(t, r.DeletedAt.Valid)
+
+	require.NoError(t, petDb.Delete(&petmodel.Pet{Common: model.Common{Id: pet.Id}}))
+	n, err := petDb.Purge(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
+
+func TestPagePet(t *testing.T) {
+	store := mempet.NewStore()
+	petDb := mempet.NewPetDomain(store).PetDb(context.Background())
+
+	for i := 0; i < 5; i++ {
+		_, err := petDb.Create(&petmodel.Pet{Name: "gugu", Type: "cat", Age: uint32(i % 3)})
+		require.NoError(t, err)
+	}
+
+	expr, err := filter.Parse("age > 0", petmodel.PetFields)
+	require.NoError(t, err)
+	orderBy, err := filter.ParseOrderBy("age desc", petmodel.PetFields)
+	require.NoError(t, err)
+
+	page := &model.Page{Size: 2, Filter: expr, OrderBy: orderBy}
+	query := &petmodel.Pet{Type: "cat"}
+
+	count, err := petDb.Count(query, page)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	var ages []uint32
+	for {
+		r, err := petDb.Page(query, page)
+		require.NoError(t, err)
+
+		var last model.Object
+		if len(r) > page.Size {
+			r = r[:page.Size]
+			last = r[len(r)-1]
+		}
+		for _, v := range r {
+			ages = append(ages, v.Age)
+		}
+
+		token := page.NextToken(last)
+		if token == "" {
+			break
+		}
+
+		page.Cursor, err = model.DecodePageToken(token, page.OrderBy, petmodel.PetFields)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []uint32{2, 1, 1}, ages)
+
+	// 总数正好是整页时没有下一页
+	page = &model.Page{Size: 3, Filter: expr, OrderBy: orderBy}
+	r, err := petDb.Page(query, page)
+	require.NoError(t, err)
+	require.Len(t, r, 3)
+}
+
+func TestTransaction(t *testing.T) {
+	store := mempet.NewStore()
+	domain := mempet.NewPetDomain(store)
+
+	err := store.Transaction(context.Background(), func(txctx context.Context) error {
+		_, err := domain.OwnerDb(txctx).Create(&petmodel.Owner{Name: "qq"})
+		require.NoError(t, err)
+
+		// 嵌套事务回滚不影响外层
+		_ = store.Transaction(txctx, func(txctx context.Context) error {
+			_, err := domain.OwnerDb(txctx).Create(&petmodel.Owner{Name: "gugu"})
+			require.NoError(t, err)
+			return errors.New("rollback")
+		})
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	r, err := domain.OwnerDb(context.Background()).List(&petmodel.Owner{}, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, r, 1)
+	require.Equal(t, "qq", r[0].Name)
+
+	err = store.Transaction(context.Background(), func(txctx context.Context) error {
+		_, err := domain.OwnerDb(txctx).Create(&petmodel.Owner{Name: "gugu"})
+		require.NoError(t, err)
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	r, err = domain.OwnerDb(context.Background()).List(&petmodel.Owner{}, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, r, 1)
+}
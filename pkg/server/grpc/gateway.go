@@ -1,36 +1,232 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
+	"net/textproto"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	errors2 "github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/win5do/golang-microservice-demo/pkg/api/adminpb"
 	gw "github.com/win5do/golang-microservice-demo/pkg/api/petpb"
+	"github.com/win5do/golang-microservice-demo/pkg/auth"
+	"github.com/win5do/golang-microservice-demo/pkg/config"
 
-	log "github.com/win5do/go-lib/logx"
+	"github.com/win5do/go-lib/errx"
 )
 
-func runGateway(gatewayAddr, grpcAddr string) error {
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
+// gateway 的 handler，按 cfg.GatewayMode 通过 grpc 连接转发或在进程内直接调用服务
+func newGateway(ctx context.Context, cfg *config.Config, svcs *Services, grpcAddr string) (http.Handler, error) {
 	jsonPb := &runtime.JSONPb{}
 	jsonPb.UseProtoNames = true
 	jsonPb.EmitUnpopulated = true
 
 	mux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, jsonPb),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithForwardResponseOption(etagHeader),
+		runtime.WithErrorHandler(errorHandler),
+		cfg.Metrics.GatewayOption(),
 	)
+
+	if cfg.GatewayMode != config.GatewayInProcess {
+		err := registerFromEndpoint(ctx, mux, cfg, svcs, grpcAddr)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.Metrics.Middleware(mux), nil
+	}
+
+	err := registerInProcess(ctx, mux, svcs)
+	if err != nil {
+		return nil, err
+	}
+
+	var handler http.Handler = mux
+	if cfg.Authenticator != nil {
+		handler, err = authMiddleware(ctx, cfg.Authenticator, mux, svcs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cfg.Metrics.Middleware(handler), nil
+}
+
+// 直接调用服务实现，没有网络开销，但不经过 grpc 的拦截器，日志、链路和 grpc 指标只在 grpc 请求上记录，认证由 authMiddleware 处理
+func registerInProcess(ctx context.Context, mux *runtime.ServeMux, svcs *Services) error {
+	err := gw.RegisterPetServiceGWServer(ctx, mux, svcs.Pet)
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
+
+	if svcs.Admin != nil {
+		err = adminpb.RegisterAdminServiceGWServer(ctx, mux, svcs.Admin)
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+	}
+	return nil
+}
+
+type rpcMethodKey struct{}
+
+// 进程内调用不经过 grpc 的认证拦截器，在 gateway 前按相同的规则认证，Principal 通过请求的 ctx 传给服务
+//
+// 请求对应的 grpc 方法由只注册了空实现的 mux 解析，annotator 在调用服务前取出方法名，请求体不会被读取
+func authMiddleware(ctx context.Context, authenticator *auth.Authenticator, mux *runtime.ServeMux, svcs *Services) (http.Handler, error) {
+	methods := runtime.NewServeMux(runtime.WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+		if p, ok := r.Context().Value(rpcMethodKey{}).(*string); ok {
+			*p, _ = runtime.RPCMethod(ctx)
+		}
+		return nil
+	}))
+
+	err := gw.RegisterPetServiceGWServer(ctx, methods, &gw.UnimplementedPetServiceServer{})
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	if svcs.Admin != nil {
+		err = adminpb.RegisterAdminServiceGWServer(ctx, methods, &adminpb.UnimplementedAdminServiceServer{})
+		if err != nil {
+			return nil, errx.WithStackOnce(err)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var method string
+		probe := r.Clone(context.WithValue(r.Context(), rpcMethodKey{}, &method))
+		probe.Body = http.NoBody
+		methods.ServeHTTP(&discardWriter{header: http.Header{}}, probe)
+
+		// 没有匹配的路由，由 gateway 返回 404
+		if method == "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		md := metadata.MD{}
+		if v := r.Header.Get("Authorization"); v != "" {
+			md.Set("authorization", v)
+		}
+		ctx, err := authenticator.Authenticate(metadata.NewIncomingContext(r.Context(), md), method)
+		if err != nil {
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	}), nil
+}
+
+// 丢弃解析方法时空实现返回的响应
+type discardWriter struct {
+	header http.Header
+}
+
+func (d *discardWriter) Header() http.Header {
+	return d.header
+}
+
+func (d *discardWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (d *discardWriter) WriteHeader(int) {}
+
+// 通过本机的 grpc 连接转发，连接随 ctx 关闭
+func registerFromEndpoint(ctx context.Context, mux *runtime.ServeMux, cfg *config.Config, svcs *Services, grpcAddr string) error {
 	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if isTLS(cfg) {
+		creds, err := loopbackCreds(cfg.TlsCert, cfg.TlsKey)
+		if err != nil {
+			return err
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	}
 
 	err := gw.RegisterPetServiceGWFromEndpoint(ctx, mux, grpcAddr, opts)
 	if err != nil {
-		return err
+		return errx.WithStackOnce(err)
 	}
 
-	log.Infof("gateway server start: %s", gatewayAddr)
-	return http.ListenAndServe(gatewayAddr, mux)
+	if svcs.Admin != nil {
+		err = adminpb.RegisterAdminServiceGWFromEndpoint(ctx, mux, grpcAddr, opts)
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+	}
+	return nil
+}
+
+// 连接本进程的 grpc server，证书不一定包含 localhost，改为校验对端证书与本地证书一致
+func loopbackCreds(certFile, keyFile string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errx.WithStackOnce(err)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], cert.Certificate[0]) {
+				return errors2.New("grpc server certificate mismatch")
+			}
+			return nil
+		},
+	}), nil
+}
+
+// If-Match 原样转为 metadata，用于乐观锁
+func headerMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "If-Match":
+		return "if-match", true
+	case "Authorization":
+		// gateway 已转发为 authorization，不再转发带前缀的副本
+		return "", false
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// 响应中带 etag 时设置 ETag 响应头
+func etagHeader(ctx context.Context, w http.ResponseWriter, msg proto.Message) error {
+	if m, ok := msg.(interface{ GetEtag() string }); ok && m.GetEtag() != "" {
+		w.Header().Set("ETag", `"`+m.GetEtag()+`"`)
+	}
+
+	return nil
+}
+
+// 数据冲突返回 409，未认证返回 401 并带上 WWW-Authenticate，其他错误使用默认的映射
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		w = &statusWriter{ResponseWriter: w, code: http.StatusConflict}
+	case codes.Unauthenticated:
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusWriter) WriteHeader(int) {
+	s.ResponseWriter.WriteHeader(s.code)
 }
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// 单端口模式，HTTP/2 且 Content-Type 为 application/grpc 的请求交给 grpc server，其他交给 gateway
+//
+// 没有 tls 时通过 h2c 支持明文 HTTP/2，grpc 客户端可以直接连接
+func mixHandler(grpcServer *grpc.Server, other http.Handler, tls bool) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		other.ServeHTTP(w, r)
+	})
+
+	if tls {
+		return h
+	}
+	return h2c.NewHandler(h, &http2.Server{})
+}
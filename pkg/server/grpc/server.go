@@ -3,63 +3,160 @@ package grpc
 import (
 	"context"
 	"net"
+	"net/http"
+	"sync"
 
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
-	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 
 	log "github.com/win5do/go-lib/logx"
 
+	"github.com/win5do/go-lib/errx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/adminpb"
 	"github.com/win5do/golang-microservice-demo/pkg/api/petpb"
-	"github.com/win5do/golang-microservice-demo/pkg/config/util"
-	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
-	petdb "github.com/win5do/golang-microservice-demo/pkg/repository/db/pet"
+	adminsvc "github.com/win5do/golang-microservice-demo/pkg/service/admin"
 	petsvc "github.com/win5do/golang-microservice-demo/pkg/service/pet"
 
 	"github.com/win5do/golang-microservice-demo/pkg/config"
+	"github.com/win5do/golang-microservice-demo/pkg/server/supervisor"
 )
 
-func Run(ctx context.Context, cfg *config.Config) {
+// 注册到 grpc server 和 gateway 的服务
+type Services struct {
+	Pet   *petsvc.PetService
+	Admin *adminsvc.AdminService // 为空时不注册
+}
+
+// 创建 grpc server 和 gateway 并添加到 sup，grpc 先于 gateway 添加，退出时 gateway 先停止
+func Setup(ctx context.Context, sup *supervisor.Supervisor, cfg *config.Config, svcs *Services) error {
 	addr := net.JoinHostPort("", cfg.GrpcPort)
 
+	// 先监听再创建 gateway，避免 gateway 的连接失败后进入重连退避
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return errx.WithStackOnce(err)
 	}
 
-	logger := log.GetLogger()
-	grpc_zap.ReplaceGrpcLogger(logger)
-	s := grpc.NewServer(
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_opentracing.UnaryServerInterceptor(),
-			grpc_prometheus.UnaryServerInterceptor,
-			grpc_zap.UnaryServerInterceptor(logger),
-			grpc_recovery.UnaryServerInterceptor(),
-		)),
-	)
-	petpb.RegisterPetServiceServer(s, petsvc.NewPetService(dbcore.NewTxImpl(), petdb.NewPetDomain()))
-
-	go func() {
-		// Run the server
-		log.Infof("grpc server start: %s", addr)
-		if err := s.Serve(lis); err != nil {
-			log.Fatalf("failed to serve: %v", err)
+	s, err := newServer(cfg, svcs)
+	if err != nil {
+		lis.Close()
+		return err
+	}
+
+	gateway, err := newGateway(ctx, cfg, svcs, addr)
+	if err != nil {
+		lis.Close()
+		return err
+	}
+
+	certFile, keyFile := cfg.TlsCert, cfg.TlsKey
+	if cfg.SinglePort {
+		// grpc 和 gateway 共用一个端口
+		server := &http.Server{
+			Addr:    addr,
+			Handler: mixHandler(s, gateway, isTLS(cfg)),
 		}
-	}()
+		sup.Add("grpc and gateway", &singlePort{
+			Service: supervisor.HTTP(server, lis, certFile, keyFile),
+			grpc:    s,
+		})
+		return nil
+	}
+
+	sup.Add("grpc", supervisor.Grpc(s, lis))
+
+	server := &http.Server{
+		Addr:    net.JoinHostPort("", cfg.GrpcGatewayPort),
+		Handler: gateway,
+	}
+	sup.Add("gateway", supervisor.HTTP(server, nil, certFile, keyFile))
+	return nil
+}
+
+// 单端口时 grpc 请求由 http server 处理
+type singlePort struct {
+	supervisor.Service
+	grpc *grpc.Server
+}
+
+// http server 已等待请求结束，ServeHTTP 的连接不支持 GracefulStop
+func (s *singlePort) Shutdown(ctx context.Context) error {
+	err := s.Service.Shutdown(ctx)
+	s.grpc.Stop()
+	return err
+}
 
-	go func() {
-		if err := runGateway(net.JoinHostPort("", cfg.GrpcGatewayPort), addr); err != nil {
-			log.Fatalf("err: %+v", err)
+var replaceLogger sync.Once
+
+// grpc 的 logger 是全局的，只替换一次
+func setGrpcLogger() {
+	replaceLogger.Do(func() {
+		grpc_zap.ReplaceGrpcLogger(log.GetLogger())
+	})
+}
+
+func newServer(cfg *config.Config, svcs *Services) (*grpc.Server, error) {
+	logger := log.GetLogger()
+	setGrpcLogger()
+
+	unary := []grpc.UnaryServerInterceptor{
+		grpc_opentracing.UnaryServerInterceptor(),
+		cfg.Metrics.Grpc.UnaryServerInterceptor(),
+		grpc_zap.UnaryServerInterceptor(logger),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		grpc_opentracing.StreamServerInterceptor(),
+		cfg.Metrics.Grpc.StreamServerInterceptor(),
+		grpc_zap.StreamServerInterceptor(logger),
+	}
+	// 认证失败的请求同样记录日志和指标
+	if cfg.Authenticator != nil {
+		unary = append(unary, cfg.Authenticator.UnaryServerInterceptor())
+		stream = append(stream, cfg.Authenticator.StreamServerInterceptor())
+	}
+	unary = append(unary, grpc_recovery.UnaryServerInterceptor())
+	stream = append(stream, grpc_recovery.StreamServerInterceptor())
+
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unary...)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(stream...)),
+	}
+
+	// 单端口时由 http server 处理 tls
+	if isTLS(cfg) && !cfg.SinglePort {
+		creds, err := credentials.NewServerTLSFromFile(cfg.TlsCert, cfg.TlsKey)
+		if err != nil {
+			return nil, errx.WithStackOnce(err)
 		}
-	}()
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s := grpc.NewServer(opts...)
+	petpb.RegisterPetServiceServer(s, svcs.Pet)
+	if svcs.Admin != nil {
+		adminpb.RegisterAdminServiceServer(s, svcs.Admin)
+	}
+	healthpb.RegisterHealthServer(s, cfg.Health.GrpcServer())
+	if cfg.GrpcReflection {
+		reflection.Register(s)
+	}
+	for name := range s.GetServiceInfo() {
+		cfg.Health.AddService(name)
+	}
+
+	// 预先生成所有方法的指标，未调用的方法也能查询到 0
+	cfg.Metrics.Grpc.InitializeMetrics(s)
+	return s, nil
+}
 
-	wg := util.GetWaitGroupInCtx(ctx)
-	wg.Add(1)
-	defer wg.Done()
-	<-ctx.Done()
-	s.GracefulStop()
+func isTLS(cfg *config.Config) bool {
+	return cfg.TlsCert != "" && cfg.TlsKey != ""
 }
@@ -0,0 +1,310 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/petpb"
+	"github.com/win5do/golang-microservice-demo/pkg/auth"
+	"github.com/win5do/golang-microservice-demo/pkg/config"
+	"github.com/win5do/golang-microservice-demo/pkg/health"
+	"github.com/win5do/golang-microservice-demo/pkg/metrics"
+	mempet "github.com/win5do/golang-microservice-demo/pkg/repository/memory/pet"
+	"github.com/win5do/golang-microservice-demo/pkg/server/supervisor"
+	petsvc "github.com/win5do/golang-microservice-demo/pkg/service/pet"
+)
+
+func TestMain(m *testing.M) {
+	// 在创建客户端前替换，避免与 Run 并发修改全局 logger
+	setGrpcLogger()
+	os.Exit(m.Run())
+}
+
+func freePort(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	return port
+}
+
+func testConfig(t *testing.T) *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.GrpcPort = freePort(t)
+	cfg.GrpcGatewayPort = freePort(t)
+	cfg.GatewayMode = config.GatewayDial
+
+	m, err := metrics.New("")
+	require.NoError(t, err)
+	cfg.Metrics = m
+	cfg.Health = health.NewChecker()
+	return cfg
+}
+
+// 启动服务，返回停止函数，停止时检查正常退出
+func start(t *testing.T, cfg *config.Config) func() {
+	store := mempet.NewStore()
+	svcs := &Services{
+		Pet: petsvc.NewPetService(store, mempet.NewPetDomain(store)),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sup := supervisor.New(time.Second)
+	require.NoError(t, Setup(ctx, sup, cfg, svcs))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sup.Run(ctx)
+	}()
+
+	addrs := []string{cfg.GrpcPort}
+	if !cfg.SinglePort {
+		addrs = append(addrs, cfg.GrpcGatewayPort)
+	}
+	for _, v := range addrs {
+		addr := net.JoinHostPort("127.0.0.1", v)
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				return false
+			}
+			conn.Close()
+			return true
+		}, 3*time.Second, 10*time.Millisecond)
+	}
+
+	return func() {
+		// 未发送请求的新连接 5s 后才会被 Shutdown 关闭
+		http.DefaultClient.CloseIdleConnections()
+		cancel()
+		require.NoError(t, <-errCh)
+	}
+}
+
+func ping(t *testing.T, addr string, opt grpc.DialOption) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, opt, grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := petpb.NewPetServiceClient(conn).Ping(ctx, &petpb.Id{Id: "test"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Id)
+}
+
+func get(t *testing.T, client *http.Client, url string) {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	require.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json"))
+}
+
+func TestSinglePort(t *testing.T) {
+	for _, mode := range []string{config.GatewayInProcess, config.GatewayDial} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.SinglePort = true
+			cfg.GatewayMode = mode
+			stop := start(t, cfg)
+			defer stop()
+
+			addr := net.JoinHostPort("127.0.0.1", cfg.GrpcPort)
+			ping(t, addr, grpc.WithInsecure())
+			get(t, http.DefaultClient, "http://"+addr+"/ping")
+			get(t, http.DefaultClient, "http://"+addr+"/v1/pets")
+		})
+	}
+}
+
+// 回传 GET 得到的完整对象时，gateway 生成的 mask 会带上 etag、deletedAt 等输出字段
+func TestUpdateMask(t *testing.T) {
+	cfg := testConfig(t)
+	stop := start(t, cfg)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort("127.0.0.1", cfg.GrpcPort), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := petpb.NewPetServiceClient(conn)
+
+	pet, err := client.CreatePet(ctx, &petpb.Pet{Name: "gugu", Type: "cat"})
+	require.NoError(t, err)
+	owner, err := client.CreateOwner(ctx, &petpb.Owner{Name: "alice", Sex: "female"})
+	require.NoError(t, err)
+
+	gwURL := "http://" + net.JoinHostPort("127.0.0.1", cfg.GrpcGatewayPort)
+	patch := func(url, body string) string {
+		req, err := http.NewRequest(http.MethodPatch, url, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(b))
+		return string(b)
+	}
+
+	body := patch(gwURL+"/v1/pets/"+pet.Id, `{"id":"`+pet.Id+`","name":"qq","etag":"`+pet.Etag+`","deletedAt":null,"createdAt":"2020-01-01T00:00:00Z"}`)
+	require.Contains(t, body, `"name":"qq"`)
+	body = patch(gwURL+"/v1/owners/"+owner.Id, `{"id":"`+owner.Id+`","name":"bob","etag":"`+owner.Etag+`","deletedAt":null}`)
+	require.Contains(t, body, `"name":"bob"`)
+}
+
+// 进程内调用时由 gateway 认证，规则与 grpc 拦截器一致
+func TestAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpc-auth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	secret := []byte("0123456789abcdef")
+	secretFile := filepath.Join(dir, "secret")
+	require.NoError(t, ioutil.WriteFile(secretFile, secret, 0600))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "alice",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}).SignedString(secret)
+	require.NoError(t, err)
+
+	for _, mode := range []string{config.GatewayInProcess, config.GatewayDial} {
+		t.Run(mode, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.GatewayMode = mode
+			cfg.Auth = auth.Config{
+				SecretFile:    secretFile,
+				PublicMethods: []string{"/pet.service.v1.PetService/Ping"},
+			}
+			cfg.Authenticator, err = auth.New(&cfg.Auth)
+			require.NoError(t, err)
+			stop := start(t, cfg)
+			defer stop()
+
+			addr := net.JoinHostPort("127.0.0.1", cfg.GrpcPort)
+			gwURL := "http://" + net.JoinHostPort("127.0.0.1", cfg.GrpcGatewayPort)
+
+			// 公开方法
+			ping(t, addr, grpc.WithInsecure())
+			get(t, http.DefaultClient, gwURL+"/ping")
+
+			do := func(method, url, body, token string) int {
+				req, err := http.NewRequest(method, url, strings.NewReader(body))
+				require.NoError(t, err)
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+				resp, err := http.DefaultClient.Do(req)
+				require.NoError(t, err)
+				defer resp.Body.Close()
+
+				_, err = ioutil.ReadAll(resp.Body)
+				require.NoError(t, err)
+				if resp.StatusCode == http.StatusUnauthorized {
+					require.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+				}
+				return resp.StatusCode
+			}
+
+			// 未带 token 或 token 无效
+			require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, gwURL+"/v1/pets", "", ""))
+			require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, gwURL+"/v1/pets", "", "invalid"))
+			require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, gwURL+"/v1/pets", `{"name":"gugu","type":"cat"}`, ""))
+
+			require.Equal(t, http.StatusOK, do(http.MethodGet, gwURL+"/v1/pets", "", token))
+			// 认证时不读取请求体
+			require.Equal(t, http.StatusOK, do(http.MethodPost, gwURL+"/v1/pets", `{"name":"gugu","type":"cat"}`, token))
+			require.Equal(t, http.StatusNotFound, do(http.MethodGet, gwURL+"/v1/unknown", "", ""))
+		})
+	}
+}
+
+// gateway 连接的地址没有主机名，无法按证书校验，改为与本地证书比较
+func TestTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpc-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile, pool := selfSigned(t, dir)
+	tlsClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+	creds := grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool}))
+
+	for _, single := range []bool{false, true} {
+		cfg := testConfig(t)
+		cfg.SinglePort = single
+		cfg.TlsCert = certFile
+		cfg.TlsKey = keyFile
+		stop := start(t, cfg)
+
+		addr := net.JoinHostPort("127.0.0.1", cfg.GrpcPort)
+		gwAddr := net.JoinHostPort("127.0.0.1", cfg.GrpcGatewayPort)
+		if single {
+			gwAddr = addr
+		}
+
+		ping(t, addr, creds)
+		get(t, tlsClient, "https://"+gwAddr+"/ping")
+		stop()
+	}
+}
+
+func selfSigned(t *testing.T, dir string) (certFile, keyFile string, pool *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/apidocs"
+)
+
+// api 文档：/swagger/{name}.swagger.json 为原始文档，/docs/{name} 为 redoc 页面
+func RegisterDocs(mux *gin.Engine) {
+	mux.GET("/swagger/:file", func(c *gin.Context) {
+		file := c.Param("file")
+		if !strings.HasSuffix(file, ".swagger.json") {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		spec, ok := apidocs.Spec(strings.TrimSuffix(file, ".swagger.json"))
+		if !ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+
+	mux.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, "/docs/"+apidocs.Names()[0])
+	})
+
+	mux.GET("/docs/:name", func(c *gin.Context) {
+		name := c.Param("name")
+		if _, ok := apidocs.Spec(name); !ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		page, err := apidocs.RedocPage(name, "/swagger/"+name+".swagger.json")
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	})
+}
@@ -49,8 +49,12 @@ func Response(c *gin.Context, err error, data interface{}) {
 		errors2.As(err, &jsonErr):
 		// *json.SyntaxError implement error, not json.SyntaxError
 		httpCode = http.StatusBadRequest
+	case errors2.Is(err, errcode2.Err_unauthenticated):
+		httpCode = http.StatusUnauthorized
 	case errors2.Is(err, errcode2.Err_forbidden):
 		httpCode = http.StatusForbidden
+	case errors2.Is(err, errcode2.Err_conflict):
+		httpCode = http.StatusConflict
 	default:
 		httpCode = http.StatusInternalServerError
 	}
@@ -0,0 +1,21 @@
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/win5do/golang-microservice-demo/pkg/config"
+	"github.com/win5do/golang-microservice-demo/pkg/server/supervisor"
+)
+
+// 在单独的端口暴露 /metrics，未配置 MetricsPort 时由 http server 暴露
+func NewMetrics(cfg *config.Config) supervisor.Service {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", cfg.Metrics.Handler())
+
+	server := &http.Server{
+		Addr:    net.JoinHostPort("", cfg.MetricsPort),
+		Handler: mux,
+	}
+	return supervisor.HTTP(server, nil, "", "")
+}
@@ -1,73 +1,25 @@
 package http
 
 import (
-	"context"
-	"crypto/tls"
 	"net"
 	"net/http"
-	"time"
 
 	"github.com/opentracing-contrib/go-gin/ginhttp"
 
-	log "github.com/win5do/go-lib/logx"
-
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
 
 	"github.com/win5do/golang-microservice-demo/pkg/config"
-	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	"github.com/win5do/golang-microservice-demo/pkg/server/supervisor"
 )
 
-func Run(ctx context.Context, cfg *config.Config) {
-	mux := SetupMux(cfg)
-
+// 由 supervisor 启动和退出
+func New(cfg *config.Config) supervisor.Service {
 	server := &http.Server{
 		Addr:    net.JoinHostPort("", cfg.HttpPort),
-		Handler: mux,
-	}
-
-	var startFn func()
-
-	if cfg.TlsCert != "" && cfg.TlsKey != "" {
-		// https
-		startFn = func() {
-			log.Infof("https server start: %v", server.Addr)
-			cer, err := tls.LoadX509KeyPair(cfg.TlsCert, cfg.TlsKey)
-			if err != nil {
-				log.Errorf("failed to load certificate and key: %v", err)
-				return
-			}
-			tlsConfig := &tls.Config{Certificates: []tls.Certificate{cer}}
-			server.TLSConfig = tlsConfig
-
-			if err := server.ListenAndServeTLS(cfg.TlsCert, cfg.TlsKey); err != nil && err != http.ErrServerClosed {
-				log.Fatalf("err: %+v", err)
-			}
-		}
-	} else {
-		// http
-		startFn = func() {
-			log.Infof("http server start: %v", server.Addr)
-			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Fatalf("err: %+v", err)
-			}
-		}
+		Handler: SetupMux(cfg),
 	}
-
-	go startFn()
-
-	wg := util.GetWaitGroupInCtx(ctx)
-	wg.Add(1)
-	defer wg.Done()
-	<-ctx.Done()
-
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		log.Errorf("server shutdown err: %+v", err)
-		return
-	}
-	log.Info("http server shutdown")
+	return supervisor.HTTP(server, nil, cfg.TlsCert, cfg.TlsKey)
 }
 
 func SetupMux(cfg *config.Config) http.Handler {
@@ -85,6 +37,13 @@ func SetupMux(cfg *config.Config) http.Handler {
 
 	pprof.Register(mux) // default is "debug/pprof"
 	Register(mux)
+	RegisterDocs(mux)
+	mux.GET("/healthz", gin.WrapF(cfg.Health.Healthz))
+	mux.GET("/readyz", gin.WrapF(cfg.Health.Readyz))
+
+	if cfg.MetricsPort == "" {
+		mux.GET("/metrics", gin.WrapH(cfg.Metrics.Handler()))
+	}
 
 	return mux
 }
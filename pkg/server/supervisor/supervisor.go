@@ -0,0 +1,189 @@
+// Package supervisor 统一管理 grpc、gateway、http 等服务的启动和退出
+package supervisor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+)
+
+// Serve 阻塞直到服务停止，正常停止返回 nil
+type Service interface {
+	Serve() error
+	Shutdown(ctx context.Context) error
+}
+
+type service struct {
+	name string
+	svc  Service
+}
+
+// 服务按添加顺序启动，按相反顺序退出，被依赖的服务应先添加
+//
+// 任一服务异常退出时停止所有服务，并把错误返回给调用方
+type Supervisor struct {
+	timeout  time.Duration
+	services []*service
+}
+
+// timeout 为所有服务退出的总超时，超时后强制关闭
+func New(timeout time.Duration) *Supervisor {
+	return &Supervisor{
+		timeout: timeout,
+	}
+}
+
+func (s *Supervisor) Add(name string, svc Service) {
+	s.services = append(s.services, &service{name: name, svc: svc})
+}
+
+// 启动所有服务，直到 ctx 取消或有服务异常退出，然后依次退出所有服务
+//
+// 返回第一个异常退出的错误，没有时返回退出过程中的错误
+func (s *Supervisor) Run(ctx context.Context) error {
+	errCh := make(chan error, len(s.services))
+	wg := &sync.WaitGroup{}
+	for _, v := range s.services {
+		wg.Add(1)
+		go func(v *service) {
+			defer wg.Done()
+
+			log.Infof("%s server start", v.name)
+			err := v.svc.Serve()
+			if err != nil {
+				errCh <- errors2.WithMessagef(err, "%s server", v.name)
+				return
+			}
+			log.Infof("%s server stopped", v.name)
+		}(v)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+		log.Errorf("err: %+v", err)
+	}
+
+	shutdownErr := s.shutdown()
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	return shutdownErr
+}
+
+func (s *Supervisor) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var err error
+	for i := len(s.services) - 1; i >= 0; i-- {
+		v := s.services[i]
+		if e := v.svc.Shutdown(ctx); e != nil {
+			log.Errorf("%s server shutdown err: %+v", v.name, e)
+			if err == nil {
+				err = errors2.WithMessagef(e, "shutdown %s server", v.name)
+			}
+			continue
+		}
+		log.Infof("%s server shutdown", v.name)
+	}
+	return err
+}
+
+type httpService struct {
+	server            *http.Server
+	lis               net.Listener
+	certFile, keyFile string
+}
+
+// lis 为 nil 时在 Serve 中监听 server.Addr，certFile 和 keyFile 都不为空时使用 tls
+func HTTP(server *http.Server, lis net.Listener, certFile, keyFile string) Service {
+	return &httpService{
+		server:   server,
+		lis:      lis,
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+}
+
+func (s *httpService) Serve() error {
+	lis := s.lis
+	if lis == nil {
+		var err error
+		lis, err = net.Listen("tcp", s.server.Addr)
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+	}
+
+	var err error
+	if s.certFile != "" && s.keyFile != "" {
+		err = s.server.ServeTLS(lis, s.certFile, s.keyFile)
+	} else {
+		err = s.server.Serve(lis)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return errx.WithStackOnce(err)
+	}
+	return nil
+}
+
+// 等待处理中的请求结束
+func (s *httpService) Shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
+	return nil
+}
+
+type grpcService struct {
+	server *grpc.Server
+	lis    net.Listener
+}
+
+func Grpc(server *grpc.Server, lis net.Listener) Service {
+	return &grpcService{
+		server: server,
+		lis:    lis,
+	}
+}
+
+func (s *grpcService) Serve() error {
+	// Stop 之后 Serve 返回 nil
+	err := s.server.Serve(s.lis)
+	if err != nil {
+		return errx.WithStackOnce(err)
+	}
+	return nil
+}
+
+// 等待处理中的请求结束，超时后强制关闭连接
+func (s *grpcService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+		return errx.WithStackOnce(ctx.Err())
+	}
+}
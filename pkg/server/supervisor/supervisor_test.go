@@ -0,0 +1,149 @@
+package supervisor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeService struct {
+	name  string
+	err   error // Serve 立即返回的错误
+	block time.Duration
+
+	mu    *sync.Mutex
+	order *[]string
+	stop  chan struct{}
+}
+
+func newFake(name string, mu *sync.Mutex, order *[]string) *fakeService {
+	return &fakeService{
+		name:  name,
+		mu:    mu,
+		order: order,
+		stop:  make(chan struct{}),
+	}
+}
+
+func (s *fakeService) Serve() error {
+	if s.err != nil {
+		return s.err
+	}
+	<-s.stop
+	return nil
+}
+
+func (s *fakeService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	*s.order = append(*s.order, s.name)
+	s.mu.Unlock()
+
+	defer close(s.stop)
+	select {
+	case <-time.After(s.block):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestSupervisor(t *testing.T) {
+	mu := &sync.Mutex{}
+	var order []string
+	sup := New(time.Second)
+	for _, v := range []string{"http", "grpc", "gateway"} {
+		sup.Add(v, newFake(v, mu, &order))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sup.Run(ctx)
+	}()
+
+	cancel()
+	require.NoError(t, <-errCh)
+	// 按添加的相反顺序退出
+	require.Equal(t, []string{"gateway", "grpc", "http"}, order)
+}
+
+func TestSupervisorError(t *testing.T) {
+	mu := &sync.Mutex{}
+	var order []string
+	failed := newFake("gateway", mu, &order)
+	failed.err = errors2.New("address already in use")
+
+	sup := New(time.Second)
+	sup.Add("grpc", newFake("grpc", mu, &order))
+	sup.Add("gateway", failed)
+
+	// 不取消 ctx，异常退出的服务也会停止其他服务
+	err := sup.Run(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "gateway server: address already in use")
+	require.Equal(t, []string{"gateway", "grpc"}, order)
+}
+
+func TestSupervisorTimeout(t *testing.T) {
+	mu := &sync.Mutex{}
+	var order []string
+	slow := newFake("slow", mu, &order)
+	slow.block = time.Minute
+
+	sup := New(50 * time.Millisecond)
+	sup.Add("slow", slow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := sup.Run(ctx)
+	require.True(t, errors2.Is(err, context.DeadlineExceeded))
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestHTTP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// 处理中的请求在退出时完成
+	started := make(chan struct{})
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	}
+
+	sup := New(time.Second)
+	sup.Add("http", HTTP(server, lis, "", ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sup.Run(ctx)
+	}()
+
+	respCh := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + lis.Addr().String())
+		if err != nil {
+			respCh <- 0
+			return
+		}
+		resp.Body.Close()
+		respCh <- resp.StatusCode
+	}()
+
+	<-started
+	cancel()
+	require.NoError(t, <-errCh)
+	require.Equal(t, http.StatusNoContent, <-respCh)
+}
@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"context"
+
+	errors2 "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/adminpb"
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/job"
+	jobmodel "github.com/win5do/golang-microservice-demo/pkg/model/job"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+)
+
+type ILockAdmin interface {
+	ListLocks(ctx context.Context, action string) ([]*dbcore.LockInfo, error)
+	ForceUnlock(ctx context.Context, action, holder string) (int64, error)
+}
+
+// 内存存储没有分布式锁，列表为空，强制释放不做任何操作
+type NoopLockAdmin struct{}
+
+func (*NoopLockAdmin) ListLocks(ctx context.Context, action string) ([]*dbcore.LockInfo, error) {
+	return nil, nil
+}
+
+func (*NoopLockAdmin) ForceUnlock(ctx context.Context, action, holder string) (int64, error) {
+	return 0, nil
+}
+
+type IElector interface {
+	Name() string
+	Identity() string
+	IsLeader() bool
+}
+
+type IScheduler interface {
+	Jobs(ctx context.Context) ([]*job.JobStatus, error)
+	Trigger(ctx context.Context, name string) (*jobmodel.JobRun, error)
+}
+
+type AdminService struct {
+	adminpb.UnimplementedAdminServiceServer
+
+	lockAdmin ILockAdmin
+	elector   IElector // 未开启选举时为空
+	scheduler IScheduler
+	// 是否允许 ReleaseLock
+	forceUnlock bool
+}
+
+func NewAdminService(lockAdmin ILockAdmin, elector IElector, scheduler IScheduler, forceUnlock bool) *AdminService {
+	return &AdminService{
+		lockAdmin:   lockAdmin,
+		elector:     elector,
+		scheduler:   scheduler,
+		forceUnlock: forceUnlock,
+	}
+}
+
+func (s *AdminService) ListLocks(ctx context.Context, in *adminpb.ListLocksRequest) (*adminpb.LockList, error) {
+	locks, err := s.lockAdmin.ListLocks(ctx, in.Action)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	r := &adminpb.LockList{}
+	for _, v := range locks {
+		r.Items = append(r.Items, &adminpb.Lock{
+			Action:    v.Action,
+			Holder:    v.Holder,
+			Mode:      string(v.Mode),
+			Holds:     int32(v.Holds),
+			Token:     v.Token,
+			CreatedAt: timestamppb.New(v.CreatedAt),
+			ExpiredAt: timestamppb.New(v.ExpiredAt),
+		})
+	}
+
+	return r, nil
+}
+
+func (s *AdminService) ReleaseLock(ctx context.Context, in *adminpb.ReleaseLockRequest) (*adminpb.ReleaseLockResponse, error) {
+	if !s.forceUnlock {
+		return nil, status.Error(codes.PermissionDenied, "force unlock disabled, see --admin-force-unlock")
+	}
+
+	if in.Action == "" {
+		return nil, status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	n, err := s.lockAdmin.ForceUnlock(ctx, in.Action, in.Holder)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	log.Infof("force release lock: %s, holder: %q, released: %d", in.Action, in.Holder, n)
+
+	return &adminpb.ReleaseLockResponse{
+		Released: n,
+	}, nil
+}
+
+func (s *AdminService) GetLeader(ctx context.Context, in *adminpb.GetLeaderRequest) (*adminpb.Leader, error) {
+	if s.elector == nil {
+		return nil, status.Error(codes.FailedPrecondition, "leader election disabled")
+	}
+
+	locks, err := s.lockAdmin.ListLocks(ctx, s.elector.Name())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	r := &adminpb.Leader{
+		Name:     s.elector.Name(),
+		Identity: s.elector.Identity(),
+		IsLeader: s.elector.IsLeader(),
+	}
+	if len(locks) > 0 {
+		r.Holder = locks[0].Holder
+		r.ExpiredAt = timestamppb.New(locks[0].ExpiredAt)
+	}
+
+	return r, nil
+}
+
+func (s *AdminService) ListJobs(ctx context.Context, in *adminpb.ListJobsRequest) (*adminpb.JobList, error) {
+	jobs, err := s.scheduler.Jobs(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	r := &adminpb.JobList{}
+	for _, v := range jobs {
+		item := &adminpb.Job{
+			Name:    v.Name,
+			Spec:    v.Spec,
+			Running: v.Running,
+			LastRun: jobRun2Pb(v.LastRun),
+		}
+		if !v.Next.IsZero() {
+			item.NextRunAt = timestamppb.New(v.Next)
+		}
+		r.Items = append(r.Items, item)
+	}
+
+	return r, nil
+}
+
+func (s *AdminService) TriggerJob(ctx context.Context, in *adminpb.TriggerJobRequest) (*adminpb.JobRun, error) {
+	run, err := s.scheduler.Trigger(ctx, in.Name)
+	if err != nil {
+		switch {
+		case errors2.Is(err, errcode.Err_not_found):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors2.Is(err, errcode.Err_conflict):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	log.Infof("trigger job: %s, run: %s", in.Name, run.Id)
+
+	return jobRun2Pb(run), nil
+}
+
+func jobRun2Pb(in *jobmodel.JobRun) *adminpb.JobRun {
+	if in == nil {
+		return nil
+	}
+
+	r := &adminpb.JobRun{
+		Id:        in.Id,
+		Job:       in.Job,
+		Status:    string(in.Status),
+		Error:     in.Error,
+		Holder:    in.Holder,
+		Manual:    in.Manual,
+		StartedAt: timestamppb.New(in.StartedAt),
+	}
+	if in.EndedAt != nil {
+		r.EndedAt = timestamppb.New(*in.EndedAt)
+	}
+	return r
+}
@@ -1,6 +1,7 @@
 package pet
 
 import (
+	"context"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -9,13 +10,18 @@ import (
 	"gorm.io/gorm"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
 )
 
 func pberr(err error) error {
 	switch {
+	case errors2.Is(err, errcode.Err_unauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
 	case errors2.Is(err, errcode.Err_forbidden):
 		return status.Error(codes.PermissionDenied, err.Error())
 	case errors2.Is(err, errcode.Err_not_found),
@@ -34,6 +40,14 @@ func time2Pb(in time.Time) *timestamp.Timestamp {
 	return timestamppb.New(in)
 }
 
+func deletedAt2Pb(in gorm.DeletedAt) *timestamp.Timestamp {
+	if !in.Valid {
+		return nil
+	}
+
+	return timestamppb.New(in.Time)
+}
+
 func pb2Time(in *timestamp.Timestamp) time.Time {
 	if in == nil {
 		return time.Time{}
@@ -41,3 +55,66 @@ func pb2Time(in *timestamp.Timestamp) time.Time {
 
 	return in.AsTime()
 }
+
+type listRequest interface {
+	GetPageSize() int32
+	GetPageToken() string
+	GetFilter() string
+	GetOrderBy() string
+	GetShowDeleted() bool
+}
+
+// 解析列表请求中的分页、过滤和排序参数
+func pbPage(in listRequest, fields filter.Fields) (*model.Page, error) {
+	size, err := model.PageSize(int(in.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := filter.Parse(in.GetFilter(), fields)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, err := filter.ParseOrderBy(in.GetOrderBy(), fields)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := model.DecodePageToken(in.GetPageToken(), orderBy, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Page{
+		Size:        size,
+		Filter:      expr,
+		OrderBy:     orderBy,
+		Cursor:      cursor,
+		ShowDeleted: in.GetShowDeleted(),
+	}, nil
+}
+
+// 与 gateway 约定，If-Match 请求头转为此 metadata
+const mdIfMatch = "if-match"
+
+// 解析乐观锁版本号，请求中没有 etag 时使用 If-Match
+// required 为 false 且没有 etag 时返回 0，表示不校验版本
+func pbVersion(ctx context.Context, etag string, required bool) (int64, error) {
+	if etag == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(mdIfMatch); len(v) > 0 {
+				etag = v[0]
+			}
+		}
+	}
+
+	if etag == "" {
+		if required {
+			return 0, errors2.Wrap(errcode.Err_invalid_params, "etag is required")
+		}
+		return 0, nil
+	}
+
+	return model.ParseEtag(etag)
+}
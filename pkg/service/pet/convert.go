@@ -1,6 +1,8 @@
 package pet
 
 import (
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
 	"github.com/win5do/golang-microservice-demo/pkg/api/petpb"
 	"github.com/win5do/golang-microservice-demo/pkg/model"
 	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
@@ -16,6 +18,8 @@ func ModelPet2PbPet(in *petmodel.Pet) *petpb.Pet {
 		Age:       in.Age,
 		Sex:       in.Sex,
 		Owned:     in.Owned,
+		Etag:      in.Etag(),
+		DeletedAt: deletedAt2Pb(in.DeletedAt),
 	}
 }
 
@@ -51,6 +55,8 @@ func ModelOwner2PbOwner(in *petmodel.Owner) *petpb.Owner {
 		Age:       in.Age,
 		Sex:       in.Sex,
 		Phone:     in.Phone,
+		Etag:      in.Etag(),
+		DeletedAt: deletedAt2Pb(in.DeletedAt),
 	}
 }
 
@@ -75,3 +81,20 @@ func ModelOwner2PbOwnerList(in []*petmodel.Owner) []*petpb.Owner {
 	}
 	return out
 }
+
+// update mask 中的 api 字段名转为 model 字段名
+func PbUpdateMask2Fields(in *fieldmaskpb.FieldMask) []string {
+	var out []string
+	for _, v := range in.GetPaths() {
+		switch v {
+		case "createdAt":
+			v = "created_at"
+		case "updatedAt":
+			v = "updated_at"
+		case "deletedAt":
+			v = "deleted_at"
+		}
+		out = append(out, v)
+	}
+	return out
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	errors2 "github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	log "github.com/win5do/go-lib/logx"
@@ -40,14 +41,36 @@ func (s *PetService) Ping(ctx context.Context, in *petpb.Id) (*petpb.Id, error)
 	}, nil
 }
 
-func (s *PetService) ListPet(ctx context.Context, in *emptypb.Empty) (*petpb.PetList, error) {
-	pets, err := s.petDomain.PetDb(ctx).List(&petmodel.Pet{}, 0, 0)
+func (s *PetService) ListPet(ctx context.Context, in *petpb.ListPetRequest) (*petpb.PetList, error) {
+	page, err := pbPage(in, petmodel.PetFields)
 	if err != nil {
 		return nil, pberr(err)
 	}
 
+	query := &petmodel.Pet{}
+	petDb := s.petDomain.PetDb(ctx)
+
+	pets, err := petDb.Page(query, page)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	total, err := petDb.Count(query, page)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	// 多取的一条不返回，只用于判断是否还有下一页
+	var last model.Object
+	if len(pets) > page.Size {
+		pets = pets[:page.Size]
+		last = pets[len(pets)-1]
+	}
+
 	out := &petpb.PetList{
-		Items: ModelPet2PbPetList(pets),
+		Items:         ModelPet2PbPetList(pets),
+		NextPageToken: page.NextToken(last),
+		TotalSize:     int32(total),
 	}
 	return out, nil
 }
@@ -70,8 +93,20 @@ func (s *PetService) CreatePet(ctx context.Context, in *petpb.Pet) (*petpb.Pet,
 	return ModelPet2PbPet(pet), nil
 }
 
-func (s *PetService) UpdatePet(ctx context.Context, in *petpb.Pet) (*petpb.Pet, error) {
-	pet, err := s.petDomain.PetDb(ctx).Update(PbPet2ModelPet(in))
+func (s *PetService) UpdatePet(ctx context.Context, in *petpb.UpdatePetRequest) (*petpb.Pet, error) {
+	if in.Pet == nil {
+		return nil, pberr(errors2.Wrap(errcode.Err_invalid_params, "pet is required"))
+	}
+
+	version, err := pbVersion(ctx, in.Pet.Etag, true)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	pet := PbPet2ModelPet(in.Pet)
+	pet.Version = version
+
+	pet, err = s.petDomain.PetDb(ctx).Update(pet, PbUpdateMask2Fields(in.UpdateMask)...)
 	if err != nil {
 		return nil, pberr(err)
 	}
@@ -79,10 +114,20 @@ func (s *PetService) UpdatePet(ctx context.Context, in *petpb.Pet) (*petpb.Pet,
 	return ModelPet2PbPet(pet), nil
 }
 
-func (s *PetService) DeletePet(ctx context.Context, in *petpb.Id) (*emptypb.Empty, error) {
-	err := s.petDomain.PetDb(ctx).Delete(&petmodel.Pet{
+func (s *PetService) DeletePet(ctx context.Context, in *petpb.DeletePetRequest) (*emptypb.Empty, error) {
+	if in.Id == "" {
+		return nil, pberr(errors2.Wrap(errcode.Err_invalid_params, "id is required"))
+	}
+
+	version, err := pbVersion(ctx, in.Etag, true)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	err = s.petDomain.PetDb(ctx).Delete(&petmodel.Pet{
 		Common: model.Common{
-			Id: in.Id,
+			Id:      in.Id,
+			Version: version,
 		},
 	})
 	if err != nil {
@@ -91,14 +136,55 @@ func (s *PetService) DeletePet(ctx context.Context, in *petpb.Id) (*emptypb.Empt
 	return &emptypb.Empty{}, nil
 }
 
-func (s *PetService) ListOwner(ctx context.Context, in *emptypb.Empty) (*petpb.OwnerList, error) {
-	owners, err := s.petDomain.OwnerDb(ctx).List(&petmodel.Owner{}, 0, 0)
+func (s *PetService) UndeletePet(ctx context.Context, in *petpb.UndeletePetRequest) (*petpb.Pet, error) {
+	version, err := pbVersion(ctx, in.Etag, false)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	pet, err := s.petDomain.PetDb(ctx).Undelete(&petmodel.Pet{
+		Common: model.Common{
+			Id:      in.Id,
+			Version: version,
+		},
+	})
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	return ModelPet2PbPet(pet), nil
+}
+
+func (s *PetService) ListOwner(ctx context.Context, in *petpb.ListOwnerRequest) (*petpb.OwnerList, error) {
+	page, err := pbPage(in, petmodel.OwnerFields)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	query := &petmodel.Owner{}
+	ownerDb := s.petDomain.OwnerDb(ctx)
+
+	owners, err := ownerDb.Page(query, page)
 	if err != nil {
 		return nil, pberr(err)
 	}
 
+	total, err := ownerDb.Count(query, page)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	// 多取的一条不返回，只用于判断是否还有下一页
+	var last model.Object
+	if len(owners) > page.Size {
+		owners = owners[:page.Size]
+		last = owners[len(owners)-1]
+	}
+
 	out := &petpb.OwnerList{
-		Items: ModelOwner2PbOwnerList(owners),
+		Items:         ModelOwner2PbOwnerList(owners),
+		NextPageToken: page.NextToken(last),
+		TotalSize:     int32(total),
 	}
 	return out, nil
 }
@@ -121,8 +207,20 @@ func (s *PetService) CreateOwner(ctx context.Context, in *petpb.Owner) (*petpb.O
 	return ModelOwner2PbOwner(owner), nil
 }
 
-func (s *PetService) UpdateOwner(ctx context.Context, in *petpb.Owner) (*petpb.Owner, error) {
-	owner, err := s.petDomain.OwnerDb(ctx).Update(PbOwner2ModelOwner(in))
+func (s *PetService) UpdateOwner(ctx context.Context, in *petpb.UpdateOwnerRequest) (*petpb.Owner, error) {
+	if in.Owner == nil {
+		return nil, pberr(errors2.Wrap(errcode.Err_invalid_params, "owner is required"))
+	}
+
+	version, err := pbVersion(ctx, in.Owner.Etag, true)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	owner := PbOwner2ModelOwner(in.Owner)
+	owner.Version = version
+
+	owner, err = s.petDomain.OwnerDb(ctx).Update(owner, PbUpdateMask2Fields(in.UpdateMask)...)
 	if err != nil {
 		return nil, pberr(err)
 	}
@@ -130,7 +228,17 @@ func (s *PetService) UpdateOwner(ctx context.Context, in *petpb.Owner) (*petpb.O
 	return ModelOwner2PbOwner(owner), nil
 }
 
-func (s *PetService) DeleteOwner(ctx context.Context, in *petpb.Id) (*emptypb.Empty, error) {
+func (s *PetService) DeleteOwner(ctx context.Context, in *petpb.DeleteOwnerRequest) (*emptypb.Empty, error) {
+	// 空 id 会查出所有关联
+	if in.Id == "" {
+		return nil, pberr(errors2.Wrap(errcode.Err_invalid_params, "id is required"))
+	}
+
+	version, err := pbVersion(ctx, in.Etag, true)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
 	rows, err := s.petDomain.OwnerPetDb(ctx).Query(&petmodel.OwnerPet{
 		OwnerId: in.Id,
 	})
@@ -144,7 +252,8 @@ func (s *PetService) DeleteOwner(ctx context.Context, in *petpb.Id) (*emptypb.Em
 
 	err = s.petDomain.OwnerDb(ctx).Delete(&petmodel.Owner{
 		Common: model.Common{
-			Id: in.Id,
+			Id:      in.Id,
+			Version: version,
 		},
 	})
 	if err != nil {
@@ -154,6 +263,25 @@ func (s *PetService) DeleteOwner(ctx context.Context, in *petpb.Id) (*emptypb.Em
 	return &emptypb.Empty{}, nil
 }
 
+func (s *PetService) UndeleteOwner(ctx context.Context, in *petpb.UndeleteOwnerRequest) (*petpb.Owner, error) {
+	version, err := pbVersion(ctx, in.Etag, false)
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	owner, err := s.petDomain.OwnerDb(ctx).Undelete(&petmodel.Owner{
+		Common: model.Common{
+			Id:      in.Id,
+			Version: version,
+		},
+	})
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	return ModelOwner2PbOwner(owner), nil
+}
+
 func (s *PetService) OwnPet(ctx context.Context, in *petpb.OwnerPet) (*petpb.OwnerPet, error) {
 	var r *petmodel.OwnerPet
 
@@ -163,25 +291,26 @@ func (s *PetService) OwnPet(ctx context.Context, in *petpb.OwnerPet) (*petpb.Own
 			OwnerId: in.OwnerId,
 		})
 		if err != nil {
-			return pberr(err)
+			return err
 		}
 
 		r = ownerJoinPet
 
 		_, err = s.petDomain.PetDb(txctx).Update(&petmodel.Pet{
 			Common: model.Common{
-				Id: in.Id,
+				Id: in.PetId,
 			},
 			Owned: true,
-		})
+		}, "owned")
 		if err != nil {
-			return pberr(err)
+			return err
 		}
 		return nil
 	})
 
+	// 事务中返回原始错误，用于判断是否需要重试
 	if err != nil {
-		return nil, err
+		return nil, pberr(err)
 	}
 
 	return &petpb.OwnerPet{
@@ -200,21 +329,25 @@ func (s *PetService) AbandonPet(ctx context.Context, in *petpb.OwnerPet) (*empty
 			OwnerId: in.OwnerId,
 		})
 		if err != nil {
-			return pberr(err)
+			return err
 		}
 
 		_, err = s.petDomain.PetDb(txctx).Update(&petmodel.Pet{
 			Common: model.Common{
-				Id: in.Id,
+				Id: in.PetId,
 			},
 			Owned: false,
-		})
+		}, "owned")
 		if err != nil {
-			return pberr(err)
+			return err
 		}
 
 		return nil
 	})
 
-	return &emptypb.Empty{}, err
+	if err != nil {
+		return nil, pberr(err)
+	}
+
+	return &emptypb.Empty{}, nil
 }
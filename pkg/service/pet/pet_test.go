@@ -7,9 +7,15 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
 	"github.com/win5do/golang-microservice-demo/pkg/api/petpb"
 	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
 	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
 	"github.com/win5do/golang-microservice-demo/pkg/model/pet/mock_pet"
 )
@@ -46,3 +52,177 @@ func TestGetPet(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, ModelPet2PbPet(out), r)
 }
+
+func TestListPet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	petDomain := mock_pet.NewMockIPetDomain(ctrl)
+	petDb := mock_pet.NewMockIPetDb(ctrl)
+	petDomain.EXPECT().PetDb(gomock.Any()).Return(petDb).AnyTimes()
+
+	after := &model.Cursor{Id: "01EW4T6T6YSRTG96J0D4V9BVPX", Order: "age desc", Values: []interface{}{int64(3)}}
+	last := &petmodel.Pet{Common: model.Common{Id: "01EW4TGRK2RA0MF1J5TSX3M88Z"}, Name: "mimi", Age: 2}
+	// 多取的一条不返回
+	out := []*petmodel.Pet{
+		{Common: model.Common{Id: "01EW4T9Q0FJXKJ0W5QDBQ5XN7S"}, Name: "gugu", Age: 3},
+		last,
+		{Common: model.Common{Id: "01EW4TK1BGZJ7M2F0XQ7V3Y8WN"}, Name: "qq", Age: 1},
+	}
+
+	expr, err := filter.Parse(`type = "cat" AND owned = false`, petmodel.PetFields)
+	require.NoError(t, err)
+
+	page := &model.Page{
+		Size:    2,
+		Filter:  expr,
+		OrderBy: []filter.Order{{Field: "age", Desc: true}},
+		Cursor:  after,
+	}
+	petDb.EXPECT().Page(&petmodel.Pet{}, page).Return(out, nil)
+	petDb.EXPECT().Count(&petmodel.Pet{}, page).Return(int64(5), nil)
+
+	r, err := mockPetSvc(petDomain).ListPet(context.Background(), &petpb.ListPetRequest{
+		PageSize:  2,
+		PageToken: model.EncodePageToken(after),
+		Filter:    `type = "cat" AND owned = false`,
+		OrderBy:   "age desc",
+	})
+	require.NoError(t, err)
+	require.Len(t, r.Items, 2)
+	require.EqualValues(t, 5, r.TotalSize)
+	require.Equal(t, model.EncodePageToken(&model.Cursor{
+		Id:     last.Id,
+		Order:  "age desc",
+		Values: []interface{}{int64(2)},
+	}), r.NextPageToken)
+
+	// 正好一整页时没有下一页
+	petDb.EXPECT().Page(&petmodel.Pet{}, page).Return(out[:2], nil)
+	petDb.EXPECT().Count(&petmodel.Pet{}, page).Return(int64(4), nil)
+	r, err = mockPetSvc(petDomain).ListPet(context.Background(), &petpb.ListPetRequest{
+		PageSize:  2,
+		PageToken: model.EncodePageToken(after),
+		Filter:    `type = "cat" AND owned = false`,
+		OrderBy:   "age desc",
+	})
+	require.NoError(t, err)
+	require.Len(t, r.Items, 2)
+	require.Empty(t, r.NextPageToken)
+
+	for _, in := range []*petpb.ListPetRequest{
+		{PageToken: "not-a-token"},
+		{PageToken: model.EncodePageToken(after), OrderBy: "age"}, // 翻页时修改排序
+		{Filter: `age > "two"`},
+		{Filter: `color = "red"`},
+		{OrderBy: "name; drop table tb_pets"},
+	} {
+		_, err = mockPetSvc(petDomain).ListPet(context.Background(), in)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), in.String())
+	}
+}
+
+func TestUpdatePet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	petDomain := mock_pet.NewMockIPetDomain(ctrl)
+	petDb := mock_pet.NewMockIPetDb(ctrl)
+	petDomain.EXPECT().PetDb(gomock.Any()).Return(petDb).AnyTimes()
+
+	id := "abc"
+	out := &petmodel.Pet{
+		Common: model.Common{
+			Id: id,
+		},
+		Name: "gugu",
+		Type: "cat",
+	}
+
+	petDb.EXPECT().Update(&petmodel.Pet{Common: model.Common{Id: id, Version: 2}}, "age", "owned", "updated_at").Return(out, nil)
+
+	r, err := mockPetSvc(petDomain).UpdatePet(context.Background(), &petpb.UpdatePetRequest{
+		Pet: &petpb.Pet{
+			Id:   id,
+			Etag: "2",
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{
+			Paths: []string{"age", "owned", "updatedAt"},
+		},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, ModelPet2PbPet(out), r)
+
+	_, err = mockPetSvc(petDomain).UpdatePet(context.Background(), &petpb.UpdatePetRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// 缺少 etag
+	_, err = mockPetSvc(petDomain).UpdatePet(context.Background(), &petpb.UpdatePetRequest{
+		Pet: &petpb.Pet{
+			Id: id,
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeletePetConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	petDomain := mock_pet.NewMockIPetDomain(ctrl)
+	petDb := mock_pet.NewMockIPetDb(ctrl)
+	petDomain.EXPECT().PetDb(gomock.Any()).Return(petDb)
+
+	id := "abc"
+	petDb.EXPECT().Delete(&petmodel.Pet{Common: model.Common{Id: id, Version: 3}}).Return(errcode.Err_conflict)
+
+	// etag 来自 If-Match
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(mdIfMatch, `W/"3"`))
+	_, err := mockPetSvc(petDomain).DeletePet(ctx, &petpb.DeletePetRequest{
+		Id: id,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+// 与 pet 一样，删除和更新 owner 必须带 etag
+func TestOwnerEtagRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := mockPetSvc(mock_pet.NewMockIPetDomain(ctrl))
+
+	_, err := svc.DeleteOwner(context.Background(), &petpb.DeleteOwnerRequest{Id: "abc"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = svc.DeleteOwner(context.Background(), &petpb.DeleteOwnerRequest{Etag: `W/"1"`})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = svc.UpdateOwner(context.Background(), &petpb.UpdateOwnerRequest{
+		Owner: &petpb.Owner{Id: "abc", Name: "alice"},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// 记录事务函数返回的错误
+type recordTransaction struct {
+	err error
+}
+
+func (s *recordTransaction) Transaction(ctx context.Context, fn func(txctx context.Context) error, opts ...model.TxOption) error {
+	s.err = fn(ctx)
+	return s.err
+}
+
+func TestOwnPetTxError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	petDomain := mock_pet.NewMockIPetDomain(ctrl)
+	ownerPetDb := mock_pet.NewMockIOwnerPetDb(ctrl)
+	petDomain.EXPECT().OwnerPetDb(gomock.Any()).Return(ownerPetDb).AnyTimes()
+	ownerPetDb.EXPECT().Create(gomock.Any()).Return(nil, errcode.Err_conflict)
+	ownerPetDb.EXPECT().Delete(gomock.Any()).Return(errcode.Err_conflict)
+
+	tx := &recordTransaction{}
+	svc := NewPetService(tx, petDomain)
+	in := &petpb.OwnerPet{PetId: "a", OwnerId: "b"}
+
+	// 事务中返回原始错误，事务实现才能判断是否重试
+	_, err := svc.OwnPet(context.Background(), in)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Equal(t, errcode.Err_conflict, tx.err)
+
+	_, err = svc.AbandonPet(context.Background(), in)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Equal(t, errcode.Err_conflict, tx.err)
+}
@@ -0,0 +1,35 @@
+package pet
+
+import (
+	"context"
+	"time"
+
+	log "github.com/win5do/go-lib/logx"
+
+	"github.com/win5do/go-lib/errx"
+)
+
+// 永久删除软删除时间超过 retention 的记录
+func (s *PetService) Purge(ctx context.Context, retention time.Duration) error {
+	before := time.Now().Add(-retention)
+
+	return s.txImpl.Transaction(ctx, func(txctx context.Context) error {
+		ownerPets, err := s.petDomain.OwnerPetDb(txctx).Purge(before)
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+
+		pets, err := s.petDomain.PetDb(txctx).Purge(before)
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+
+		owners, err := s.petDomain.OwnerDb(txctx).Purge(before)
+		if err != nil {
+			return errx.WithStackOnce(err)
+		}
+
+		log.Infof("purge deleted before %s, pets: %d, owners: %d, owner-pets: %d", before, pets, owners, ownerPets)
+		return nil
+	})
+}
@@ -1,25 +1,48 @@
 package db_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	log "github.com/win5do/go-lib/logx"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	"github.com/win5do/golang-microservice-demo/pkg/model"
+	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
+	"github.com/win5do/golang-microservice-demo/pkg/repository/db/migration"
+	petdb "github.com/win5do/golang-microservice-demo/pkg/repository/db/pet"
 	integration_test "github.com/win5do/golang-microservice-demo/pkg/test/integration"
 )
 
+// 在 TestMain 中连接后初始化
+var (
+	DB        *dbcore.DB
+	PetDomain petmodel.IPetDomain
+	TxImpl    model.ITransaction
+)
+
 func TestMain(m *testing.M) {
 	if integration_test.SkipInCi() {
 		return
 	}
-	dbcore.Connect(&dbcore.DBConfig{
-		DSN: util.GetEnvOrDefault("DB_DSN", "root:123456@(127.0.0.1:3306)/go-demo"),
-	})
 	log.SetLogger(log.NewLogger(zapcore.DebugLevel))
+	var err error
+	DB, err = dbcore.Connect(context.Background(), &dbcore.DBConfig{
+		Driver: util.GetEnvOrDefault("DB_DRIVER", dbcore.DriverMysql),
+		DSN:    util.GetEnvOrDefault("DB_DSN", "root:123456@(127.0.0.1:3306)/go-demo"),
+	})
+	if err != nil {
+		log.Fatalf("err: %+v", err)
+	}
+	if _, err := migration.Up(context.Background(), DB, 0); err != nil {
+		log.Fatalf("err: %+v", err)
+	}
+	PetDomain = petdb.NewPetDomain(DB)
+	TxImpl = dbcore.NewTxImpl(DB)
 	os.Exit(m.Run())
 }
 
@@ -29,7 +52,8 @@ func TestUlid(t *testing.T) {
 }
 
 func TestCreateDatabase(t *testing.T) {
-	dbcore.CreateDatabase(&dbcore.DBConfig{
+	err := dbcore.CreateDatabase(&dbcore.DBConfig{
 		DSN: "root:123456@(127.0.0.1:3306)/not-exists",
 	})
+	require.NoError(t, err)
 }
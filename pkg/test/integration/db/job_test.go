@@ -0,0 +1,93 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
+	"github.com/win5do/golang-microservice-demo/pkg/config/util"
+	"github.com/win5do/golang-microservice-demo/pkg/job"
+	jobmodel "github.com/win5do/golang-microservice-demo/pkg/model/job"
+	dbjob "github.com/win5do/golang-microservice-demo/pkg/repository/db/job"
+)
+
+func TestJobLock(t *testing.T) {
+	ctx, cancel := util.NewWaitGroupCtx()
+	defer cancel()
+
+	release := make(chan struct{})
+	newScheduler := func(identity string) *job.Scheduler {
+		s := job.NewScheduler(ctx, identity, dbjob.NewJobRunDb(DB), job.NewDbLocker(DB, identity))
+		require.NoError(t, s.Register(&job.Job{
+			Name: "test-job",
+			Spec: "@hourly",
+			Run: func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+		}))
+		return s
+	}
+	a, b := newScheduler("a"), newScheduler("b")
+
+	run, err := a.Trigger(context.Background(), "test-job")
+	require.NoError(t, err)
+
+	// 其他副本正在执行
+	_, err = b.Trigger(context.Background(), "test-job")
+	require.True(t, errors2.Is(err, errcode.Err_conflict))
+
+	// 本副本正在执行，holder 相同的锁会重入，由本地状态互斥
+	_, err = a.Trigger(context.Background(), "test-job")
+	require.True(t, errors2.Is(err, errcode.Err_conflict))
+
+	close(release)
+
+	var last *jobmodel.JobRun
+	for i := 0; i < 50; i++ {
+		jobs, err := b.Jobs(context.Background())
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		last = jobs[0].LastRun
+		if last != nil && last.Status != jobmodel.RunRunning {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	require.Equal(t, run.Id, last.Id)
+	require.Equal(t, jobmodel.RunSucceeded, last.Status)
+	require.Equal(t, "a", last.Holder)
+	require.NotNil(t, last.EndedAt)
+
+	cancel()
+	util.GetWaitGroupInCtx(ctx).Wait()
+}
+
+func TestJobRunSlot(t *testing.T) {
+	runs := dbjob.NewJobRunDb(DB)
+	slot := time.Now().Truncate(time.Second)
+	newRun := func(scheduledAt *time.Time) *jobmodel.JobRun {
+		return &jobmodel.JobRun{
+			Job:         "test-slot",
+			StartedAt:   time.Now(),
+			ScheduledAt: scheduledAt,
+			Status:      jobmodel.RunRunning,
+		}
+	}
+
+	_, err := runs.Create(context.Background(), newRun(&slot))
+	require.NoError(t, err)
+	_, err = runs.Create(context.Background(), newRun(&slot))
+	require.True(t, errors2.Is(err, errcode.Err_conflict))
+
+	// 手动触发不受限制
+	for i := 0; i < 2; i++ {
+		_, err = runs.Create(context.Background(), newRun(nil))
+		require.NoError(t, err)
+	}
+}
@@ -1,11 +1,14 @@
 package db_test
 
 import (
+	"context"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
 )
 
@@ -22,7 +25,7 @@ func TestLock(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			locker := dbcore.NewLockDb(action, holder, 10*time.Second)
+			locker := dbcore.NewLockDb(DB, action, holder, 10*time.Second)
 
 			if _, err := locker.Lock(); err != nil {
 				t.Logf("not hold the lock, err: %+v", err)
@@ -36,3 +39,141 @@ func TestLock(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestTryLockFor(t *testing.T) {
+	a := dbcore.NewLockDb(DB, "test-try", "a", 10*time.Second)
+	b := dbcore.NewLockDb(DB, "test-try", "b", 10*time.Second)
+
+	ok, err := a.TryLockFor(time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = b.TryLockFor(time.Second)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	go func() {
+		time.Sleep(time.Second)
+		_ = a.UnLock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, b.LockContext(ctx))
+	require.NoError(t, b.UnLock())
+}
+
+func TestFencingToken(t *testing.T) {
+	a := dbcore.NewLockDb(DB, "test-fencing", "a", 3*time.Second)
+	b := dbcore.NewLockDb(DB, "test-fencing", "b", 3*time.Second)
+
+	ok, err := a.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	token := a.Token()
+	require.NoError(t, dbcore.CheckFencingToken(DB.Get(context.Background()), "test-fencing", token))
+	require.NoError(t, a.UnLock())
+
+	select {
+	case <-a.Lost():
+	default:
+		t.Fatal("lost should be closed after unlock")
+	}
+
+	ok, err = b.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Greater(t, b.Token(), token)
+	require.Error(t, dbcore.CheckFencingToken(DB.Get(context.Background()), "test-fencing", token))
+
+	// 模拟租约过期后被其他持有者抢占
+	err = DB.Get(context.Background()).Exec("UPDATE tb_lock_holders SET token = token + 100 WHERE action = ?", "test-fencing").Error
+	require.NoError(t, err)
+
+	ctx, cancel := dbcore.LeaseContext(context.Background(), b)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("lease should be lost")
+	}
+	require.Equal(t, int64(0), b.Token())
+	require.NoError(t, b.UnLock())
+}
+
+func TestReentrantLock(t *testing.T) {
+	a := dbcore.NewLockDb(DB, "test-reentrant", "a", 10*time.Second)
+	b := dbcore.NewLockDb(DB, "test-reentrant", "b", 10*time.Second)
+
+	for i := 0; i < 2; i++ {
+		ok, err := a.Lock()
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	require.NoError(t, a.UnLock())
+	ok, err := b.Lock()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, a.UnLock())
+	ok, err = b.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, b.UnLock())
+
+	// holder 相同的不同 Locker 重入，全部释放后其他持有者才能获取
+	c := dbcore.NewLockDb(DB, "test-reentrant", "a", 10*time.Second)
+	ok, err = a.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = c.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, a.Token(), c.Token())
+
+	require.NoError(t, a.UnLock())
+	ok, err = b.Lock()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, c.UnLock())
+	ok, err = b.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, b.UnLock())
+}
+
+func TestSharedLock(t *testing.T) {
+	shared := func(holder string) dbcore.Locker {
+		return dbcore.NewLockDb(DB, "test-shared", holder, 10*time.Second, dbcore.WithLockMode(dbcore.LockShared))
+	}
+	a, b := shared("a"), shared("b")
+	c := dbcore.NewLockDb(DB, "test-shared", "c", 10*time.Second)
+
+	for _, v := range []dbcore.Locker{a, b} {
+		ok, err := v.Lock()
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	ok, err := c.Lock()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	admin := dbcore.NewLockAdmin(DB)
+	locks, err := admin.ListLocks(context.Background(), "test-shared")
+	require.NoError(t, err)
+	require.Len(t, locks, 2)
+
+	n, err := admin.ForceUnlock(context.Background(), "test-shared", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	ok, err = c.Lock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, c.UnLock())
+	require.NoError(t, a.UnLock())
+	require.NoError(t, b.UnLock())
+}
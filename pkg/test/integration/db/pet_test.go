@@ -2,19 +2,19 @@ package db_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
+	"github.com/win5do/golang-microservice-demo/pkg/api/errcode"
 	"github.com/win5do/golang-microservice-demo/pkg/model"
+	"github.com/win5do/golang-microservice-demo/pkg/model/filter"
 	petmodel "github.com/win5do/golang-microservice-demo/pkg/model/pet"
-	"github.com/win5do/golang-microservice-demo/pkg/repository/db/dbcore"
-	petdb "github.com/win5do/golang-microservice-demo/pkg/repository/db/pet"
 )
 
-var PetDomain petmodel.IPetDomain = petdb.NewPetDomain()
-var TxImpl model.ITransaction = dbcore.NewTxImpl()
-
 func TestCreatePet(t *testing.T) {
 	_, err := PetDomain.PetDb(context.Background()).Create(&petmodel.Pet{
 		Name: "gugu",
@@ -62,3 +62,123 @@ func TestTransaction(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestPagePet(t *testing.T) {
+	petDb := PetDomain.PetDb(context.Background())
+
+	expr, err := filter.Parse(`type = "cat" AND owned = false`, petmodel.PetFields)
+	require.NoError(t, err)
+
+	page := &model.Page{
+		Size:    2,
+		Filter:  expr,
+		OrderBy: []filter.Order{{Field: "created_at", Desc: true}},
+	}
+	first, err := petDb.Page(&petmodel.Pet{}, page)
+	require.NoError(t, err)
+
+	require.LessOrEqual(t, len(first), page.Limit())
+	if len(first) <= page.Size {
+		return
+	}
+
+	last := first[page.Size-1]
+	page.Cursor = &model.Cursor{Id: last.Id, Values: []interface{}{last.CreatedAt}}
+	second, err := petDb.Page(&petmodel.Pet{}, page)
+	require.NoError(t, err)
+
+	for _, v := range second {
+		require.False(t, v.CreatedAt.After(last.CreatedAt))
+		require.False(t, v.Owned)
+	}
+}
+
+func TestUpdatePetFields(t *testing.T) {
+	petDb := PetDomain.PetDb(context.Background())
+
+	pet, err := petDb.Create(&petmodel.Pet{
+		Name:  "gugu",
+		Type:  "cat",
+		Age:   2,
+		Owned: true,
+	})
+	require.NoError(t, err)
+
+	r, err := petDb.Update(&petmodel.Pet{
+		Common: model.Common{
+			Id: pet.Id,
+		},
+		Name: "ignored",
+	}, "age", "owned")
+	require.NoError(t, err)
+	require.Equal(t, "gugu", r.Name)
+	require.Zero(t, r.Age)
+	require.False(t, r.Owned)
+}
+
+func TestUpdatePetConflict(t *testing.T) {
+	petDb := PetDomain.PetDb(context.Background())
+
+	pet, err := petDb.Create(&petmodel.Pet{
+		Name: "gugu",
+		Type: "cat",
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pet.Version)
+
+	r, err := petDb.Update(&petmodel.Pet{
+		Common: model.Common{
+			Id:      pet.Id,
+			Version: pet.Version,
+		},
+		Age: 1,
+	}, "age")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, r.Version)
+
+	// 使用旧版本号
+	_, err = petDb.Update(&petmodel.Pet{
+		Common: model.Common{
+			Id:      pet.Id,
+			Version: pet.Version,
+		},
+		Age: 2,
+	}, "age")
+	require.True(t, errors.Is(err, errcode.Err_conflict))
+
+	err = petDb.Delete(&petmodel.Pet{Common: model.Common{Id: pet.Id, Version: pet.Version}})
+	require.True(t, errors.Is(err, errcode.Err_conflict))
+
+	// 没有 id 时不能按版本号删除
+	err = petDb.Delete(&petmodel.Pet{Common: model.Common{Version: r.Version}})
+	require.True(t, errors.Is(err, errcode.Err_invalid_params))
+	_, err = petDb.Get(pet.Id)
+	require.NoError(t, err)
+}
+
+func TestSoftDeletePet(t *testing.T) {
+	petDb := PetDomain.PetDb(context.Background())
+
+	pet, err := petDb.Create(&petmodel.Pet{
+		Name: "gugu",
+		Type: "cat",
+	})
+	require.NoError(t, err)
+
+	err = petDb.Delete(&petmodel.Pet{Common: model.Common{Id: pet.Id}})
+	require.NoError(t, err)
+
+	_, err = petDb.Get(pet.Id)
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	r, err := petDb.Undelete(&petmodel.Pet{Common: model.Common{Id: pet.Id}})
+	require.NoError(t, err)
+	require.False(t, r.DeletedAt.Valid)
+
+	// 未删除的记录不能恢复
+	_, err = petDb.Undelete(&petmodel.Pet{Common: model.Common{Id: pet.Id}})
+	require.True(t, errors.Is(err, errcode.Err_conflict))
+
+	_, err = petDb.Purge(time.Now())
+	require.NoError(t, err)
+}